@@ -34,6 +34,11 @@ import (
 
 //go:generate mockery --name Client --structname Client --output mocks
 
+var (
+	_ flow.AccountProofClient  = (Client)(nil)
+	_ flow.UserSignatureClient = (Client)(nil)
+)
+
 type Client interface {
 	// Ping is used to check if the access node is alive and healthy.
 	Ping(ctx context.Context) error
@@ -39,10 +39,10 @@ import (
 	"github.com/onflow/flow-go-sdk"
 )
 
-const EmulatorHost = "127.0.0.1:3569"
-const TestnetHost = "access.devnet.nodes.onflow.org:9000"
+const EmulatorHost = flow.EmulatorGRPCHost
+const TestnetHost = flow.TestnetGRPCHost
 const CanarynetHost = "access.canary.nodes.onflow.org:9000"
-const MainnetHost = "access.mainnet.nodes.onflow.org:9000"
+const MainnetHost = flow.MainnetGRPCHost
 
 // ClientOption is a configuration option for the client.
 type ClientOption func(*options)
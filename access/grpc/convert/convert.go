@@ -567,6 +567,7 @@ func CollectionGuaranteeToMessage(g flow.CollectionGuarantee) *entities.Collecti
 		ReferenceBlockId: g.ReferenceBlockID.Bytes(),
 		Signature:        g.Signature,
 		SignerIndices:    g.SignerIndices,
+		ClusterChainId:   []byte(g.ClusterChainID),
 	}
 }
 
@@ -642,6 +643,7 @@ func MessageToCollectionGuarantee(m *entities.CollectionGuarantee) (flow.Collect
 		ReferenceBlockID: flow.HashToID(m.ReferenceBlockId),
 		Signature:        m.Signature,
 		SignerIndices:    m.SignerIndices,
+		ClusterChainID:   flow.ChainID(m.GetClusterChainId()),
 	}, nil
 }
 
@@ -966,6 +968,7 @@ func MessageToExecutionResult(execResult *entities.ExecutionResult) (*flow.Execu
 			NumberOfTransactions: uint16(chunk.NumberOfTransactions),
 			Index:                chunk.Index,
 			EndState:             flow.BytesToStateCommitment(chunk.EndState),
+			ServiceEventCount:    uint16(chunk.ServiceEventCount),
 		}
 	}
 
@@ -981,6 +984,7 @@ func MessageToExecutionResult(execResult *entities.ExecutionResult) (*flow.Execu
 		BlockID:          flow.BytesToID(execResult.BlockId),
 		Chunks:           chunks,
 		ServiceEvents:    serviceEvents,
+		ExecutionDataID:  flow.BytesToID(execResult.ExecutionDataId),
 	}, nil
 }
 
@@ -996,6 +1000,7 @@ func ExecutionResultToMessage(result flow.ExecutionResult) (*entities.ExecutionR
 			NumberOfTransactions: uint32(chunk.NumberOfTransactions),
 			Index:                chunk.Index,
 			EndState:             IdentifierToMessage(flow.Identifier(chunk.EndState)),
+			ServiceEventCount:    uint32(chunk.ServiceEventCount),
 		}
 	}
 
@@ -1012,6 +1017,7 @@ func ExecutionResultToMessage(result flow.ExecutionResult) (*entities.ExecutionR
 		BlockId:          result.BlockID.Bytes(),
 		Chunks:           chunks,
 		ServiceEvents:    serviceEvents,
+		ExecutionDataId:  IdentifierToMessage(result.ExecutionDataID),
 	}, nil
 }
 
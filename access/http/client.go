@@ -31,9 +31,9 @@ import (
 )
 
 const (
-	EmulatorHost  = "http://127.0.0.1:8888/v1"
-	TestnetHost   = "https://rest-testnet.onflow.org/v1"
-	MainnetHost   = "https://rest-mainnet.onflow.org/v1"
+	EmulatorHost  = flow.EmulatorRESTHost
+	TestnetHost   = flow.TestnetRESTHost
+	MainnetHost   = flow.MainnetRESTHost
 	CanarynetHost = "https://rest-canary.onflow.org/v1"
 )
 
@@ -19,12 +19,16 @@
 package flow
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/onflow/cadence"
+
+	"github.com/onflow/flow-go-sdk/crypto"
 )
 
 // AccountProofNonceMinLenBytes is the minimum length of account proof nonces in bytes.
@@ -70,3 +74,310 @@ func EncodeAccountProofMessage(address Address, appID, nonceHex string) ([]byte,
 
 	return msg, nil
 }
+
+// AccountProofSignature is a signature of an account proof, as provided by a wallet
+// in the FCL login flow.
+type AccountProofSignature struct {
+	// KeyIndex is the index of the account key that produced the signature.
+	KeyIndex  uint32
+	Signature []byte
+}
+
+// AccountProofVerificationMode is the way account proof signatures are verified.
+type AccountProofVerificationMode int
+
+const (
+	// AccountProofVerifyOffline fetches the account keys and verifies the signatures locally.
+	// This is the default mode, and the only mode producing per-signature results.
+	AccountProofVerifyOffline AccountProofVerificationMode = iota
+	// AccountProofVerifyScript verifies the signatures on-chain with a Cadence script.
+	AccountProofVerifyScript
+)
+
+// AccountProofClient is the subset of the Access API client used to verify account proofs.
+//
+// It is implemented by the gRPC and HTTP access clients.
+type AccountProofClient interface {
+	GetAccountAtLatestBlock(ctx context.Context, address Address) (*Account, error)
+	ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error)
+}
+
+// AccountProofOption is a configuration option for account proof verification.
+type AccountProofOption func(*AccountProofVerificationMode)
+
+// WithAccountProofVerificationMode sets the verification mode of account proofs.
+func WithAccountProofVerificationMode(mode AccountProofVerificationMode) AccountProofOption {
+	return func(m *AccountProofVerificationMode) {
+		*m = mode
+	}
+}
+
+// AccountSignatureResult is the verification result of a single signature.
+type AccountSignatureResult struct {
+	KeyIndex uint32
+	Valid    bool
+	// Weight is the weight of the key counted towards the threshold,
+	// zero if the signature is invalid or its key was already counted.
+	Weight int
+	// Reason explains why the signature is invalid.
+	Reason string
+}
+
+// AccountProofResult is the detailed result of an account proof verification.
+type AccountProofResult struct {
+	// Valid is true if all the signatures are valid and their keys reach the weight threshold.
+	Valid bool
+	// Mode is the mode the proof was verified with.
+	Mode AccountProofVerificationMode
+	// TotalWeight is the total weight of the keys with valid signatures. Only set in offline mode.
+	TotalWeight int
+	// Signatures are the results of each signature, in the input order. Only set in offline mode.
+	Signatures []AccountSignatureResult
+}
+
+// VerifyAccountProof verifies the account proof signatures of an FCL login.
+//
+// The signed message is the account proof message built with EncodeAccountProofMessage,
+// prefixed with the UserDomainTag. The proof is valid if all the signatures are valid
+// for non-revoked keys of the account, and the total weight of the distinct keys reaches
+// AccountKeyWeightThreshold.
+//
+// An error is returned if the inputs are invalid or the account can't be fetched. An invalid proof
+// doesn't return an error, the details are reported in the result.
+func VerifyAccountProof(
+	ctx context.Context,
+	client AccountProofClient,
+	address Address,
+	appID string,
+	nonce string,
+	signatures []AccountProofSignature,
+	opts ...AccountProofOption,
+) (*AccountProofResult, error) {
+	mode := AccountProofVerifyOffline
+	for _, apply := range opts {
+		apply(&mode)
+	}
+
+	message, err := EncodeAccountProofMessage(address, appID, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if len(signatures) == 0 {
+		return &AccountProofResult{Mode: mode}, nil
+	}
+
+	switch mode {
+	case AccountProofVerifyOffline:
+		account, err := client.GetAccountAtLatestBlock(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", address, err)
+		}
+
+		results, totalWeight, valid := verifyAccountSignatures(address, account.Keys, message, signatures)
+		return &AccountProofResult{
+			Valid:       valid,
+			Mode:        mode,
+			TotalWeight: totalWeight,
+			Signatures:  results,
+		}, nil
+
+	case AccountProofVerifyScript:
+		valid, err := verifyAccountSignaturesScript(ctx, client, address, message, signatures)
+		if err != nil {
+			return nil, err
+		}
+		return &AccountProofResult{
+			Valid: valid,
+			Mode:  mode,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported account proof verification mode %d", mode)
+	}
+}
+
+// verifyAccountSignatures verifies user domain signatures of the message against the keys of an account.
+//
+// It returns the result of each signature, the total weight of the distinct keys with valid signatures,
+// and whether all the signatures are valid and reach the weight threshold.
+func verifyAccountSignatures(
+	address Address,
+	keys []*AccountKey,
+	message []byte,
+	signatures []AccountProofSignature,
+) ([]AccountSignatureResult, int, bool) {
+	compositeSignatures := make([]CompositeSignature, len(signatures))
+	for i, signature := range signatures {
+		compositeSignatures[i] = CompositeSignature{
+			Address:   address,
+			KeyIndex:  signature.KeyIndex,
+			Signature: signature.Signature,
+		}
+	}
+
+	results, weights, valid := verifyWeightedSignatures(message, compositeSignatures, map[Address][]*AccountKey{address: keys})
+	return results, weights[address], valid
+}
+
+// verifyWeightedSignatures verifies user domain signatures of the message against the keys of
+// the signing accounts.
+//
+// It returns the result of each signature, the total weight of the distinct keys with valid
+// signatures of each account, and whether all the signatures are valid and the keys of each
+// account reach the weight threshold.
+func verifyWeightedSignatures(
+	message []byte,
+	signatures []CompositeSignature,
+	keys map[Address][]*AccountKey,
+) ([]AccountSignatureResult, map[Address]int, bool) {
+	signedMessage := append(UserDomainTag[:], message...)
+
+	type accountKeyID struct {
+		address Address
+		index   uint32
+	}
+	keysByID := make(map[accountKeyID]*AccountKey)
+	for address, accountKeys := range keys {
+		for _, key := range accountKeys {
+			keysByID[accountKeyID{address: address, index: key.Index}] = key
+		}
+	}
+
+	results := make([]AccountSignatureResult, len(signatures))
+	weights := make(map[Address]int)
+	counted := make(map[accountKeyID]bool, len(signatures))
+	valid := true
+
+	for i, signature := range signatures {
+		id := accountKeyID{address: signature.Address, index: signature.KeyIndex}
+		result := AccountSignatureResult{KeyIndex: signature.KeyIndex}
+		result.Valid, result.Reason = verifyAccountSignature(keysByID[id], signature.Signature, signedMessage)
+
+		// the weight of a key is only counted once
+		if result.Valid && !counted[id] {
+			counted[id] = true
+			result.Weight = keysByID[id].Weight
+			weights[signature.Address] += result.Weight
+		}
+		valid = valid && result.Valid
+		results[i] = result
+	}
+
+	// the keys of each signing account must reach the threshold
+	for _, signature := range signatures {
+		if weights[signature.Address] < AccountKeyWeightThreshold {
+			valid = false
+		}
+	}
+
+	return results, weights, valid
+}
+
+// verifyAccountSignature verifies a signature with an account key, and returns the reason
+// of the failure if the signature is invalid.
+func verifyAccountSignature(key *AccountKey, signature []byte, signedMessage []byte) (bool, string) {
+	if key == nil {
+		return false, "account key not found"
+	}
+	if key.Revoked {
+		return false, "account key is revoked"
+	}
+
+	hasher, err := crypto.NewHasher(key.HashAlgo)
+	if err != nil {
+		return false, fmt.Sprintf("unsupported hash algorithm %s", key.HashAlgo)
+	}
+	valid, err := key.PublicKey.Verify(signature, signedMessage, hasher)
+	if err != nil {
+		return false, fmt.Sprintf("failed to verify signature: %s", err)
+	}
+	if !valid {
+		return false, "invalid signature"
+	}
+	return true, ""
+}
+
+// verifyAccountSignaturesScript verifies user domain signatures of the message on-chain,
+// using the keys of the account at the latest block.
+func verifyAccountSignaturesScript(
+	ctx context.Context,
+	client AccountProofClient,
+	address Address,
+	message []byte,
+	signatures []AccountProofSignature,
+) (bool, error) {
+	keyIndexes := make([]cadence.Value, len(signatures))
+	encodedSignatures := make([]cadence.Value, len(signatures))
+	for i, signature := range signatures {
+		keyIndexes[i] = cadence.NewInt(int(signature.KeyIndex))
+		encodedSignatures[i] = cadence.String(hex.EncodeToString(signature.Signature))
+	}
+
+	result, err := client.ExecuteScriptAtLatestBlock(ctx, verifyAccountSignaturesScriptCode, []cadence.Value{
+		cadence.NewAddress(address),
+		cadence.String(hex.EncodeToString(message)),
+		cadence.NewArray(keyIndexes),
+		cadence.NewArray(encodedSignatures),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to execute signature verification script: %w", err)
+	}
+
+	valid, ok := result.(cadence.Bool)
+	if !ok {
+		return false, fmt.Errorf("unexpected signature verification script result %s", result)
+	}
+	return bool(valid), nil
+}
+
+// verifyAccountSignaturesScriptCode verifies user domain signatures of a hex encoded message
+// with the keys of an account. Like offline verification, it fails if any key is missing or revoked.
+var verifyAccountSignaturesScriptCode = []byte(`
+import Crypto
+
+access(all) fun main(
+	address: Address,
+	message: String,
+	keyIndexes: [Int],
+	signatures: [String],
+): Bool {
+	let keyList = Crypto.KeyList()
+	let keys = getAccount(address).keys
+
+	for keyIndex in keyIndexes {
+		if let key = keys.get(keyIndex: keyIndex) {
+			if key.isRevoked {
+				return false
+			}
+			keyList.add(
+				PublicKey(
+					publicKey: key.publicKey.publicKey,
+					signatureAlgorithm: key.publicKey.signatureAlgorithm
+				),
+				hashAlgorithm: key.hashAlgorithm,
+				weight: key.weight / 1000.0,
+			)
+		} else {
+			return false
+		}
+	}
+
+	let signatureSet: [Crypto.KeyListSignature] = []
+	var i = 0
+	for signature in signatures {
+		signatureSet.append(
+			Crypto.KeyListSignature(
+				keyIndex: i,
+				signature: signature.decodeHex()
+			)
+		)
+		i = i + 1
+	}
+
+	return keyList.verify(
+		signatureSet: signatureSet,
+		signedData: message.decodeHex(),
+		domainSeparationTag: "FLOW-V0.0-user",
+	)
+}
+`)
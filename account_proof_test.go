@@ -19,11 +19,17 @@
 package flow
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"testing"
 
+	"github.com/onflow/cadence"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk/crypto"
 )
 
 func TestEncodeAccountProofMessage(t *testing.T) {
@@ -74,3 +80,160 @@ func TestEncodeAccountProofMessage(t *testing.T) {
 		})
 	}
 }
+
+// accountProofClient is a stand-in of the Access API serving a single account.
+type accountProofClient struct {
+	account *Account
+	// scriptResult is returned by script executions, which record their arguments
+	scriptResult cadence.Value
+	scriptArgs   []cadence.Value
+}
+
+func (c *accountProofClient) GetAccountAtLatestBlock(_ context.Context, address Address) (*Account, error) {
+	if address != c.account.Address {
+		return nil, errors.New("account not found")
+	}
+	return c.account, nil
+}
+
+func (c *accountProofClient) ExecuteScriptAtLatestBlock(_ context.Context, _ []byte, arguments []cadence.Value) (cadence.Value, error) {
+	c.scriptArgs = arguments
+	return c.scriptResult, nil
+}
+
+func newAccountProofSigner(t *testing.T, sigAlgo crypto.SignatureAlgorithm, hashAlgo crypto.HashAlgorithm) crypto.InMemorySigner {
+	seed := make([]byte, crypto.MinSeedLength)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	sk, err := crypto.GeneratePrivateKey(sigAlgo, seed)
+	require.NoError(t, err)
+	signer, err := crypto.NewInMemorySigner(sk, hashAlgo)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	ctx := context.Background()
+	address := HexToAddress("ABC123DEF456")
+	appID := "AWESOME-APP-ID"
+	nonce := "3037366134636339643564623330316636626239323161663465346131393662"
+
+	signers := []crypto.InMemorySigner{
+		newAccountProofSigner(t, crypto.ECDSA_P256, crypto.SHA3_256),
+		newAccountProofSigner(t, crypto.ECDSA_secp256k1, crypto.SHA2_256),
+		newAccountProofSigner(t, crypto.ECDSA_P256, crypto.SHA3_256),
+		newAccountProofSigner(t, crypto.ECDSA_P256, crypto.SHA3_256),
+	}
+	weights := []int{500, 500, 1000, 1000}
+
+	client := &accountProofClient{account: &Account{Address: address}}
+	for i, signer := range signers {
+		client.account.Keys = append(client.account.Keys, &AccountKey{
+			Index:     uint32(i),
+			PublicKey: signer.PublicKey(),
+			SigAlgo:   signer.PublicKey().Algorithm(),
+			HashAlgo:  signer.Hasher.Algorithm(),
+			Weight:    weights[i],
+		})
+	}
+	// the last key is revoked
+	client.account.Keys[3].Revoked = true
+
+	message, err := EncodeAccountProofMessage(address, appID, nonce)
+	require.NoError(t, err)
+
+	sign := func(keyIndex uint32) AccountProofSignature {
+		signature, err := SignUserMessage(signers[keyIndex], message)
+		require.NoError(t, err)
+		return AccountProofSignature{KeyIndex: keyIndex, Signature: signature}
+	}
+
+	t.Run("Multiple signatures reaching the threshold", func(t *testing.T) {
+		result, err := VerifyAccountProof(ctx, client, address, appID, nonce, []AccountProofSignature{sign(0), sign(1)})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, 1000, result.TotalWeight)
+		require.Len(t, result.Signatures, 2)
+		assert.True(t, result.Signatures[1].Valid)
+		assert.Equal(t, 500, result.Signatures[1].Weight)
+	})
+
+	t.Run("Single full weight signature", func(t *testing.T) {
+		result, err := VerifyAccountProof(ctx, client, address, appID, nonce, []AccountProofSignature{sign(2)})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+	})
+
+	t.Run("Weight below threshold", func(t *testing.T) {
+		// a duplicate signature is only counted once
+		result, err := VerifyAccountProof(ctx, client, address, appID, nonce, []AccountProofSignature{sign(0), sign(0)})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, 500, result.TotalWeight)
+		assert.True(t, result.Signatures[1].Valid)
+		assert.Equal(t, 0, result.Signatures[1].Weight)
+	})
+
+	t.Run("Revoked key", func(t *testing.T) {
+		result, err := VerifyAccountProof(ctx, client, address, appID, nonce, []AccountProofSignature{sign(3)})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, "account key is revoked", result.Signatures[0].Reason)
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		// signed by another key, without the domain tag, or for another app
+		wrongKey := sign(0)
+		wrongKey.KeyIndex = 2
+		noTag, err := signers[2].Sign(message)
+		require.NoError(t, err)
+
+		for _, signature := range []AccountProofSignature{wrongKey, {KeyIndex: 2, Signature: noTag}} {
+			result, err := VerifyAccountProof(ctx, client, address, appID, nonce, []AccountProofSignature{signature})
+			require.NoError(t, err)
+			assert.False(t, result.Valid)
+			assert.Equal(t, "invalid signature", result.Signatures[0].Reason)
+		}
+
+		result, err := VerifyAccountProof(ctx, client, address, "OTHER-APP-ID", nonce, []AccountProofSignature{sign(2)})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Invalid signature among valid ones", func(t *testing.T) {
+		result, err := VerifyAccountProof(ctx, client, address, appID, nonce, []AccountProofSignature{sign(2), sign(3)})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, 1000, result.TotalWeight)
+	})
+
+	t.Run("Unknown key or account", func(t *testing.T) {
+		result, err := VerifyAccountProof(ctx, client, address, appID, nonce, []AccountProofSignature{{KeyIndex: 10}})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, "account key not found", result.Signatures[0].Reason)
+
+		_, err = VerifyAccountProof(ctx, client, HexToAddress("01"), appID, nonce, []AccountProofSignature{sign(2)})
+		assert.Error(t, err)
+
+		_, err = VerifyAccountProof(ctx, client, address, appID, "222222", []AccountProofSignature{sign(2)})
+		assert.ErrorIs(t, err, ErrInvalidNonce)
+	})
+
+	t.Run("Script mode", func(t *testing.T) {
+		client.scriptResult = cadence.NewBool(true)
+		result, err := VerifyAccountProof(
+			ctx, client, address, appID, nonce,
+			[]AccountProofSignature{sign(0), sign(1)},
+			WithAccountProofVerificationMode(AccountProofVerifyScript),
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, AccountProofVerifyScript, result.Mode)
+
+		require.Len(t, client.scriptArgs, 4)
+		assert.Equal(t, cadence.NewAddress(address), client.scriptArgs[0])
+		assert.Equal(t, cadence.String(hex.EncodeToString(message)), client.scriptArgs[1])
+		assert.Equal(t, cadence.NewArray([]cadence.Value{cadence.NewInt(0), cadence.NewInt(1)}), client.scriptArgs[2])
+	})
+}
@@ -21,10 +21,14 @@ package flow
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrInvalidAddress is returned when an address is not a valid account address on a chain.
+var ErrInvalidAddress = errors.New("invalid address")
+
 // Address represents the 8 byte address of an account.
 type Address [AddressLength]byte
 
@@ -115,6 +119,17 @@ func chainCustomizer(chain ChainID) uint64 {
 	}
 }
 
+// addressChains are the known chains, in the order they are reported by DetectChain.
+var addressChains = []ChainID{
+	Mainnet,
+	Testnet,
+	Emulator,
+	Localnet,
+	Benchnet,
+	BftTestnet,
+	MonotonicEmulator,
+}
+
 // EmptyAddress is the empty address (0x0000000000000000).
 var EmptyAddress = Address{}
 
@@ -224,6 +239,11 @@ func (a *Address) uint64() uint64 {
 func generateAddress(chain ChainID, state addressState) Address {
 	index := uint64(state)
 
+	// monotonic addresses are the index itself
+	if chain == MonotonicEmulator {
+		return uint64ToAddress(index)
+	}
+
 	// Multiply the index GF(2) vector by the code generator matrix
 	address := uint64(0)
 	for i := 0; i < linearCodeK; i++ {
@@ -246,12 +266,19 @@ func generateAddress(chain ChainID, state addressState) Address {
 // this address has been generated. Such a test would require an on-chain check.
 func (a *Address) IsValid(chain ChainID) bool {
 	codeWord := a.uint64()
-	codeWord ^= chainCustomizer(chain)
+	if chain == MonotonicEmulator {
+		return codeWord > 0 && codeWord <= maxState
+	}
 
+	codeWord ^= chainCustomizer(chain)
 	if codeWord == 0 {
 		return false
 	}
+	return isValidCodeWord(codeWord)
+}
 
+// isValidCodeWord returns true if the code word belongs to the [64,45]-code.
+func isValidCodeWord(codeWord uint64) bool {
 	// Multiply the code word GF(2)-vector by the parity-check matrix
 	parity := uint(0)
 	for i := 0; i < linearCodeN; i++ {
@@ -263,6 +290,66 @@ func (a *Address) IsValid(chain ChainID) bool {
 	return parity == 0
 }
 
+// Index returns the addressing state used to generate the address on the given chain,
+// which is the index an AddressGenerator must be set to in order to produce it.
+//
+// An error wrapping ErrInvalidAddress is returned if the address is not a valid
+// account address on the chain.
+func (a Address) Index(chain ChainID) (uint64, error) {
+	if !isAddressChain(chain) {
+		return 0, fmt.Errorf("chain ID [%s] is invalid or does not support address generation", chain)
+	}
+	if !a.IsValid(chain) {
+		return 0, fmt.Errorf("%w: %s is not valid on %s", ErrInvalidAddress, a.HexWithPrefix(), chain)
+	}
+
+	codeWord := a.uint64()
+	if chain == MonotonicEmulator {
+		return codeWord, nil
+	}
+	return decodeCodeWord(codeWord ^ chainCustomizer(chain)), nil
+}
+
+// DetectChain returns the known chains the address is a valid account address on.
+//
+// Emulator, Localnet, Benchnet and BftTestnet share the same addressing, so an address
+// valid on one of them is reported for all of them. An empty result means the address
+// does not belong to any known chain.
+func DetectChain(address Address) []ChainID {
+	var chains []ChainID
+	for _, chain := range addressChains {
+		if address.IsValid(chain) {
+			chains = append(chains, chain)
+		}
+	}
+	return chains
+}
+
+func isAddressChain(chain ChainID) bool {
+	for _, c := range addressChains {
+		if c == chain {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeCodeWord returns the addressing state of a valid code word.
+//
+// The last k bits of the code word are multiplied by the inverse of the square
+// sub-matrix of G formed by its first k columns.
+func decodeCodeWord(codeWord uint64) uint64 {
+	word := uint64(0)
+	codeWord >>= linearCodeN - linearCodeK
+	for i := 0; i < linearCodeK; i++ {
+		if codeWord&1 == 1 {
+			word ^= inverseMatrixRows[i]
+		}
+		codeWord >>= 1
+	}
+	return word
+}
+
 // invalid code-words in the [64,45] code
 // these constants are used to generate non-Flow-Mainnet addresses
 const (
@@ -313,3 +400,23 @@ var parityCheckMatrixColumns = [linearCodeN]uint{
 	0x0067c, 0x0059d, 0x004eb, 0x003b4,
 	0x0036a, 0x002d9, 0x001c7, 0x0003f,
 }
+
+// Rows of the inverse I of the generator sub-matrix, I = sub(G)^(-1).
+// sub(G) is the square sub-matrix of G formed by its first k columns, which makes
+// it an invertible (k x k) matrix with coefficients in GF(2). Each row is converted
+// into a big endian integer representation of the GF(2) raw vector.
+// I is used to recover the addressing state from account addresses.
+var inverseMatrixRows = [linearCodeK]uint64{
+	0x14b4ae9336c9, 0x1a5a57499b64, 0x0d2d2ba4cdb2, 0x069695d266d9,
+	0x134b4ae9336c, 0x09a5a57499b6, 0x04d2d2ba4cdb, 0x1269695d266d,
+	0x1934b4ae9336, 0x0c9a5a57499b, 0x164d2d2ba4cd, 0x1b269695d266,
+	0x0d934b4ae933, 0x16c9a5a57499, 0x1b64d2d2ba4c, 0x0db269695d26,
+	0x06d934b4ae93, 0x136c9a5a5749, 0x19b64d2d2ba4, 0x0cdb269695d2,
+	0x066d934b4ae9, 0x1336c9a5a574, 0x099b64d2d2ba, 0x04cdb269695d,
+	0x1266d934b4ae, 0x09336c9a5a57, 0x1499b64d2d2b, 0x1a4cdb269695,
+	0x1d266d934b4a, 0x0e9336c9a5a5, 0x17499b64d2d2, 0x0ba4cdb26969,
+	0x15d266d934b4, 0x0ae9336c9a5a, 0x057499b64d2d, 0x12ba4cdb2696,
+	0x095d266d934b, 0x14ae9336c9a5, 0x1a57499b64d2, 0x0d2ba4cdb269,
+	0x1695d266d934, 0x0b4ae9336c9a, 0x05a57499b64d, 0x12d2ba4cdb26,
+	0x09695d266d93,
+}
@@ -321,3 +321,50 @@ func TestAddressesIntersection(t *testing.T) {
 		}
 	}
 }
+
+func TestAddressIndex(t *testing.T) {
+	// seed random generator
+	rand.Seed(time.Now().UnixNano())
+
+	for _, chain := range []ChainID{Mainnet, Testnet, Emulator, MonotonicEmulator} {
+		// service address
+		index, err := ServiceAddress(chain).Index(chain)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(serviceAddressState), index)
+
+		// random states round trip
+		for i := 0; i < 100; i++ {
+			state := uint64(rand.Intn(maxState-1) + 1)
+			address := NewAddressGenerator(chain).SetIndex(uint(state)).Address()
+			index, err := address.Index(chain)
+			require.NoError(t, err)
+			assert.Equal(t, state, index)
+		}
+
+		// the zero address is not an account address
+		_, err = zeroAddress(chain).Index(chain)
+		assert.ErrorIs(t, err, ErrInvalidAddress)
+	}
+
+	// address of another chain
+	_, err := HexToAddress("0xf8d6e0586b0a20c7").Index(Mainnet)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+
+	// unsupported chain
+	_, err = ServiceAddress(Mainnet).Index("flow-unknown")
+	assert.Error(t, err)
+}
+
+func TestDetectChain(t *testing.T) {
+	transient := []ChainID{Emulator, Localnet, Benchnet, BftTestnet}
+
+	assert.Equal(t, []ChainID{Mainnet}, DetectChain(HexToAddress("0xe467b9dd11fa00df")))
+	assert.Equal(t, []ChainID{Mainnet}, DetectChain(HexToAddress("0x1654653399040a61")))
+	assert.Equal(t, []ChainID{Testnet}, DetectChain(HexToAddress("0x8c5303eaa26202d6")))
+	assert.Equal(t, []ChainID{Testnet}, DetectChain(HexToAddress("0x7e60df042a9c0868")))
+	assert.Equal(t, transient, DetectChain(HexToAddress("0xf8d6e0586b0a20c7")))
+	assert.Equal(t, []ChainID{MonotonicEmulator}, DetectChain(HexToAddress("0x01")))
+
+	assert.Empty(t, DetectChain(EmptyAddress))
+	assert.Empty(t, DetectChain(uint64ToAddress(invalidCodeWord)))
+}
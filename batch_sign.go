@@ -0,0 +1,411 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// SignatureRole indicates which part of a transaction a signature is generated over.
+type SignatureRole int
+
+const (
+	// PayloadSignatureRole is used by proposers and authorizers to sign the transaction payload.
+	PayloadSignatureRole SignatureRole = iota
+	// EnvelopeSignatureRole is used by the payer to sign the transaction envelope.
+	EnvelopeSignatureRole
+)
+
+// String returns the string representation of this signature role.
+func (r SignatureRole) String() string {
+	switch r {
+	case PayloadSignatureRole:
+		return "payload"
+	case EnvelopeSignatureRole:
+		return "envelope"
+	default:
+		return "unknown"
+	}
+}
+
+// A BatchSignItem is a single signature to generate with SignTransactionsBatch.
+type BatchSignItem struct {
+	Transaction *Transaction
+	Role        SignatureRole
+	Address     Address
+	KeyIndex    uint32
+	Signer      crypto.Signer
+}
+
+// A BatchSignResult is the outcome of signing a single BatchSignItem.
+//
+// If Err is nil, Signature has been added to the item's transaction.
+type BatchSignResult struct {
+	Signature []byte
+	Err       error
+}
+
+// A BatchSignOption configures a call to SignTransactionsBatch.
+type BatchSignOption func(*BatchSignConfig)
+
+// BatchSignConfig holds the configuration of SignTransactionsBatch.
+type BatchSignConfig struct {
+	// Concurrency is the maximum number of signing calls in flight.
+	Concurrency int
+	// SignerRateLimit is the maximum number of signing calls per second for a single signer.
+	// A value of zero disables rate limiting.
+	SignerRateLimit float64
+	// MaxBatchSize is the maximum number of messages passed in a single call to a crypto.BatchSigner.
+	MaxBatchSize int
+}
+
+// WithBatchSignConcurrency sets the maximum number of signing calls in flight.
+//
+// The default concurrency is 16.
+func WithBatchSignConcurrency(concurrency int) BatchSignOption {
+	return func(config *BatchSignConfig) {
+		config.Concurrency = concurrency
+	}
+}
+
+// WithBatchSignRateLimit sets the maximum number of signing calls per second for a single signer.
+//
+// Rate limiting is disabled by default.
+func WithBatchSignRateLimit(callsPerSecond float64) BatchSignOption {
+	return func(config *BatchSignConfig) {
+		config.SignerRateLimit = callsPerSecond
+	}
+}
+
+// WithBatchSignMaxSize sets the maximum number of messages passed in a single call to a crypto.BatchSigner.
+//
+// The default batch size is 100.
+func WithBatchSignMaxSize(size int) BatchSignOption {
+	return func(config *BatchSignConfig) {
+		config.MaxBatchSize = size
+	}
+}
+
+// DefaultBatchSignConfig returns the default configuration of SignTransactionsBatch:
+// a concurrency of 16, batches of up to 100 messages and no rate limiting.
+func DefaultBatchSignConfig() *BatchSignConfig {
+	return &BatchSignConfig{
+		Concurrency:  16,
+		MaxBatchSize: 100,
+	}
+}
+
+// SignTransactionsBatch generates the signatures described by the items and adds them to their transactions.
+//
+// All payload signatures are generated first, since the envelope message of a transaction
+// includes its payload signatures. Envelope signatures of a transaction are not generated
+// if any of its payload signatures failed. Signatures are added to the transactions
+// in their canonical order.
+//
+// Signing calls are made concurrently, up to the configured concurrency. Signers are
+// identified by their public key, or by their value if they have no public key, so that
+// signers sharing a key share a rate limit and can be batched together. Signers without a
+// public key whose value isn't comparable can't be identified, and are never grouped.
+// Signers implementing crypto.BatchSigner sign several messages in a single call.
+//
+// Single signing calls of a signer are made sequentially, so a signer which isn't safe for
+// concurrent use, like crypto.InMemorySigner, can be shared by items. Batches of a
+// crypto.BatchSigner are signed concurrently, batch signers must be safe for concurrent use.
+//
+// The returned results are in the same order as the items.
+func SignTransactionsBatch(ctx context.Context, items []BatchSignItem, opts ...BatchSignOption) []BatchSignResult {
+	config := DefaultBatchSignConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	if config.MaxBatchSize < 1 {
+		config.MaxBatchSize = 1
+	}
+
+	b := &batchSigner{
+		config:   config,
+		items:    items,
+		results:  make([]BatchSignResult, len(items)),
+		limiters: make(map[any]*rateLimiter),
+	}
+
+	for i, item := range items {
+		switch {
+		case item.Transaction == nil:
+			b.results[i].Err = errors.New("batch sign: missing transaction")
+		case item.Signer == nil:
+			b.results[i].Err = errors.New("batch sign: missing signer")
+		case item.Role != PayloadSignatureRole && item.Role != EnvelopeSignatureRole:
+			b.results[i].Err = fmt.Errorf("batch sign: unknown signature role %d", item.Role)
+		}
+	}
+
+	b.signRole(ctx, PayloadSignatureRole)
+
+	// envelopes can't be signed over incomplete payload signatures
+	failed := make(map[*Transaction]struct{})
+	for i, item := range items {
+		if item.Role == PayloadSignatureRole && b.results[i].Err != nil {
+			failed[item.Transaction] = struct{}{}
+		}
+	}
+	for i, item := range items {
+		if _, ok := failed[item.Transaction]; ok && item.Role == EnvelopeSignatureRole && b.results[i].Err == nil {
+			b.results[i].Err = errors.New("batch sign: payload signing of the transaction failed")
+		}
+	}
+
+	b.signRole(ctx, EnvelopeSignatureRole)
+
+	return b.results
+}
+
+type batchSigner struct {
+	config  *BatchSignConfig
+	items   []BatchSignItem
+	results []BatchSignResult
+
+	limitersMu sync.Mutex
+	limiters   map[any]*rateLimiter
+}
+
+// batchSignJob is a single signing call, over one or several items sharing the same signer.
+type batchSignJob struct {
+	signerID any
+	signer   crypto.Signer
+	indices  []int
+	messages [][]byte
+}
+
+// signRole generates and adds all pending signatures of the given role.
+func (b *batchSigner) signRole(ctx context.Context, role SignatureRole) {
+	// messages are computed sequentially, since computing them normalizes
+	// the transaction arguments
+	messages := make(map[*Transaction][]byte)
+	// queues are run concurrently, the jobs of a queue sequentially
+	var queues [][]*batchSignJob
+	singles := make(map[any]int)
+	batches := make(map[any]*batchSignJob)
+
+	for i, item := range b.items {
+		if item.Role != role || b.results[i].Err != nil {
+			continue
+		}
+
+		message, ok := messages[item.Transaction]
+		if !ok {
+			if role == PayloadSignatureRole {
+				message = item.Transaction.PayloadMessage()
+			} else {
+				message = item.Transaction.EnvelopeMessage()
+			}
+			message = append(TransactionDomainTag[:], message...)
+			messages[item.Transaction] = message
+		}
+
+		id := signerID(item.Signer)
+		_, isBatchSigner := item.Signer.(crypto.BatchSigner)
+		if !isBatchSigner || id == nil {
+			job := &batchSignJob{
+				signerID: id,
+				signer:   item.Signer,
+				indices:  []int{i},
+				messages: [][]byte{message},
+			}
+			queue, ok := singles[id]
+			if !ok || id == nil {
+				queue = len(queues)
+				queues = append(queues, nil)
+				singles[id] = queue
+			}
+			queues[queue] = append(queues[queue], job)
+			continue
+		}
+
+		job, ok := batches[id]
+		if !ok || len(job.indices) == b.config.MaxBatchSize {
+			job = &batchSignJob{
+				signerID: id,
+				signer:   item.Signer,
+			}
+			batches[id] = job
+			queues = append(queues, []*batchSignJob{job})
+		}
+		job.indices = append(job.indices, i)
+		job.messages = append(job.messages, message)
+	}
+
+	sem := make(chan struct{}, b.config.Concurrency)
+	var wg sync.WaitGroup
+	for _, queue := range queues {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(queue []*batchSignJob) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, job := range queue {
+				b.runJob(ctx, job)
+			}
+		}(queue)
+	}
+	wg.Wait()
+
+	// signatures are added in item order, the transaction keeps them in canonical order
+	for i, item := range b.items {
+		if item.Role != role || b.results[i].Err != nil {
+			continue
+		}
+		if role == PayloadSignatureRole {
+			item.Transaction.AddPayloadSignature(item.Address, item.KeyIndex, b.results[i].Signature)
+		} else {
+			item.Transaction.AddEnvelopeSignature(item.Address, item.KeyIndex, b.results[i].Signature)
+		}
+	}
+}
+
+// runJob generates the signatures of a job and records them in the job items' results.
+//
+// Each job writes to distinct results, no synchronization is needed.
+func (b *batchSigner) runJob(ctx context.Context, job *batchSignJob) {
+	setErr := func(err error) {
+		for _, i := range job.indices {
+			b.results[i].Err = err
+		}
+	}
+
+	if err := b.limiter(job.signerID).wait(ctx); err != nil {
+		setErr(err)
+		return
+	}
+
+	if len(job.messages) == 1 {
+		sig, err := crypto.NewContextSigner(job.signer).SignContext(ctx, job.messages[0])
+		if err != nil {
+			setErr(fmt.Errorf("batch sign: failed to sign: %w", err))
+			return
+		}
+		b.results[job.indices[0]].Signature = sig
+		return
+	}
+
+	sigs, err := job.signer.(crypto.BatchSigner).SignBatch(ctx, job.messages)
+	if err != nil {
+		setErr(fmt.Errorf("batch sign: failed to sign batch: %w", err))
+		return
+	}
+	if len(sigs) != len(job.messages) {
+		setErr(fmt.Errorf("batch sign: signer returned %d signatures for %d messages", len(sigs), len(job.messages)))
+		return
+	}
+	for j, i := range job.indices {
+		b.results[i].Signature = sigs[j]
+	}
+}
+
+// limiter returns the rate limiter of the given signer, or nil if signing calls are not rate limited.
+func (b *batchSigner) limiter(id any) *rateLimiter {
+	if b.config.SignerRateLimit <= 0 {
+		return nil
+	}
+
+	b.limitersMu.Lock()
+	defer b.limitersMu.Unlock()
+
+	// signers that can't be identified get their own limiter
+	if id == nil {
+		return newRateLimiter(b.config.SignerRateLimit)
+	}
+
+	limiter, ok := b.limiters[id]
+	if !ok {
+		limiter = newRateLimiter(b.config.SignerRateLimit)
+		b.limiters[id] = limiter
+	}
+	return limiter
+}
+
+// signerID returns a comparable value identifying the signer: its signing key, or the signer
+// itself if it has no public key. Nil is returned if the signer can't be identified.
+func signerID(signer crypto.Signer) any {
+	pk := signer.PublicKey()
+	if pk != nil {
+		return fmt.Sprintf("%s/%x", pk.Algorithm(), pk.Encode())
+	}
+	if !reflect.ValueOf(signer).Comparable() {
+		return nil
+	}
+	return signer
+}
+
+// rateLimiter spaces out calls so that they don't exceed a given rate.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(callsPerSecond float64) *rateLimiter {
+	return &rateLimiter{
+		interval: time.Duration(float64(time.Second) / callsPerSecond),
+	}
+}
+
+// wait blocks until the next call is allowed or the context is done.
+//
+// A nil limiter never blocks.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
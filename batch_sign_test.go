@@ -0,0 +1,211 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/test"
+)
+
+// countingBatchSigner is a crypto.BatchSigner counting its batch signing calls.
+//
+// It is safe for concurrent use, unlike crypto.InMemorySigner.
+type countingBatchSigner struct {
+	privateKey crypto.PrivateKey
+	batchCalls *atomic.Int32
+}
+
+func (s countingBatchSigner) Sign(message []byte) ([]byte, error) {
+	return s.privateKey.Sign(message, crypto.NewSHA3_256())
+}
+
+func (s countingBatchSigner) PublicKey() crypto.PublicKey {
+	return s.privateKey.PublicKey()
+}
+
+func (s countingBatchSigner) SignBatch(_ context.Context, messages [][]byte) ([][]byte, error) {
+	s.batchCalls.Add(1)
+	sigs := make([][]byte, len(messages))
+	for i, message := range messages {
+		sig, err := s.Sign(message)
+		if err != nil {
+			return nil, err
+		}
+		sigs[i] = sig
+	}
+	return sigs, nil
+}
+
+type failingSigner struct{}
+
+func (failingSigner) Sign([]byte) ([]byte, error) {
+	return nil, errors.New("signing failed")
+}
+
+func (failingSigner) PublicKey() crypto.PublicKey {
+	return nil
+}
+
+func newTestPrivateKey(t *testing.T) crypto.PrivateKey {
+	seed := make([]byte, crypto.MinSeedLength)
+	sk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, seed)
+	require.NoError(t, err)
+	return sk
+}
+
+func TestSignTransactionsBatch(t *testing.T) {
+	addresses := test.AddressGenerator()
+	proposer := addresses.New()
+	payer := addresses.New()
+
+	newTx := func() *flow.Transaction {
+		return flow.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("Hello, World!") } }`)).
+			SetProposalKey(proposer, 0, 42).
+			SetPayer(payer).
+			AddAuthorizer(proposer)
+	}
+
+	t.Run("Payload and envelope signatures", func(t *testing.T) {
+		sk := newTestPrivateKey(t)
+		batchCalls := &atomic.Int32{}
+		payerSigner := countingBatchSigner{sk, batchCalls}
+
+		var items []flow.BatchSignItem
+		var txs []*flow.Transaction
+		for i := 0; i < 10; i++ {
+			tx := newTx()
+			txs = append(txs, tx)
+			// envelope items are listed first, they must still be signed last
+			items = append(items,
+				flow.BatchSignItem{Transaction: tx, Role: flow.EnvelopeSignatureRole, Address: payer, KeyIndex: 0, Signer: payerSigner},
+				flow.BatchSignItem{Transaction: tx, Role: flow.PayloadSignatureRole, Address: proposer, KeyIndex: 1, Signer: test.MockSigner([]byte{1})},
+				flow.BatchSignItem{Transaction: tx, Role: flow.PayloadSignatureRole, Address: proposer, KeyIndex: 0, Signer: test.MockSigner([]byte{0})},
+			)
+		}
+
+		results := flow.SignTransactionsBatch(
+			context.Background(),
+			items,
+			flow.WithBatchSignConcurrency(4),
+			flow.WithBatchSignMaxSize(4),
+		)
+		require.Len(t, results, len(items))
+		for _, result := range results {
+			require.NoError(t, result.Err)
+		}
+
+		// 10 envelopes in batches of 4
+		assert.Equal(t, int32(3), batchCalls.Load())
+
+		for _, tx := range txs {
+			require.Len(t, tx.PayloadSignatures, 2)
+			assert.Equal(t, uint32(0), tx.PayloadSignatures[0].KeyIndex)
+			assert.Equal(t, uint32(1), tx.PayloadSignatures[1].KeyIndex)
+
+			require.Len(t, tx.EnvelopeSignatures, 1)
+			message := append(flow.TransactionDomainTag[:], tx.EnvelopeMessage()...)
+			valid, err := sk.PublicKey().Verify(tx.EnvelopeSignatures[0].Signature, message, crypto.NewSHA3_256())
+			require.NoError(t, err)
+			assert.True(t, valid)
+		}
+	})
+
+	t.Run("Shared in-memory signer", func(t *testing.T) {
+		sk := newTestPrivateKey(t)
+		signer, err := crypto.NewInMemorySigner(sk, crypto.SHA3_256)
+		require.NoError(t, err)
+
+		var items []flow.BatchSignItem
+		for i := 0; i < 200; i++ {
+			items = append(items, flow.BatchSignItem{Transaction: newTx(), Role: flow.EnvelopeSignatureRole, Address: payer, Signer: signer})
+		}
+
+		results := flow.SignTransactionsBatch(context.Background(), items, flow.WithBatchSignConcurrency(8))
+		for i, result := range results {
+			require.NoError(t, result.Err)
+			tx := items[i].Transaction
+			message := append(flow.TransactionDomainTag[:], tx.EnvelopeMessage()...)
+			valid, err := sk.PublicKey().Verify(result.Signature, message, crypto.NewSHA3_256())
+			require.NoError(t, err)
+			assert.True(t, valid)
+		}
+	})
+
+	t.Run("Failed payload signature", func(t *testing.T) {
+		tx := newTx()
+		otherTx := newTx()
+
+		results := flow.SignTransactionsBatch(context.Background(), []flow.BatchSignItem{
+			{Transaction: tx, Role: flow.PayloadSignatureRole, Address: proposer, Signer: failingSigner{}},
+			{Transaction: tx, Role: flow.EnvelopeSignatureRole, Address: payer, Signer: test.MockSigner([]byte{2})},
+			{Transaction: otherTx, Role: flow.EnvelopeSignatureRole, Address: payer, Signer: test.MockSigner([]byte{2})},
+			{Transaction: otherTx, Role: flow.EnvelopeSignatureRole, Address: payer},
+		})
+		require.Len(t, results, 4)
+
+		assert.Error(t, results[0].Err)
+		assert.Error(t, results[1].Err)
+		assert.NoError(t, results[2].Err)
+		assert.Error(t, results[3].Err)
+
+		assert.Empty(t, tx.PayloadSignatures)
+		assert.Empty(t, tx.EnvelopeSignatures)
+		assert.Len(t, otherTx.EnvelopeSignatures, 1)
+	})
+
+	t.Run("Rate limit of signers without public key", func(t *testing.T) {
+		var items []flow.BatchSignItem
+		for i := 0; i < 6; i++ {
+			items = append(items, flow.BatchSignItem{Transaction: newTx(), Role: flow.PayloadSignatureRole, Address: proposer, Signer: failingSigner{}})
+		}
+
+		start := time.Now()
+		results := flow.SignTransactionsBatch(context.Background(), items, flow.WithBatchSignRateLimit(50))
+		// the first call isn't delayed, the next ones are spaced by 20ms
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		for _, result := range results {
+			assert.Error(t, result.Err)
+		}
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		tx := newTx()
+		results := flow.SignTransactionsBatch(ctx, []flow.BatchSignItem{
+			{Transaction: tx, Role: flow.PayloadSignatureRole, Address: proposer, Signer: countingBatchSigner{newTestPrivateKey(t), &atomic.Int32{}}},
+		}, flow.WithBatchSignRateLimit(10))
+		require.Len(t, results, 1)
+
+		assert.ErrorIs(t, results[0].Err, context.Canceled)
+		assert.Empty(t, tx.PayloadSignatures)
+	})
+}
@@ -0,0 +1,291 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// BlockEncodingVersion is a version of the canonical block encoding used by the protocol
+// to compute block IDs and payload hashes.
+type BlockEncodingVersion int
+
+const (
+	// BlockEncodingCurrent is the encoding used by current protocol versions: header timestamps
+	// are encoded in Unix milliseconds, and the IDs of collection guarantees, execution receipts
+	// and timeout certificates cover their signatures.
+	BlockEncodingCurrent BlockEncodingVersion = iota
+	// BlockEncodingLegacy is the encoding used by earlier protocol versions: header timestamps
+	// are encoded in Unix nanoseconds, and signatures are excluded from the IDs of collection
+	// guarantees, execution receipts and timeout certificates.
+	BlockEncodingLegacy
+)
+
+func (v BlockEncodingVersion) String() string {
+	switch v {
+	case BlockEncodingCurrent:
+		return "current"
+	case BlockEncodingLegacy:
+		return "legacy"
+	default:
+		return "unknown"
+	}
+}
+
+// blockEncodingVersions lists the supported encodings, from the most recent.
+var blockEncodingVersions = []BlockEncodingVersion{BlockEncodingCurrent, BlockEncodingLegacy}
+
+// ErrBlockIDMismatch is returned when a block ID doesn't match the ID computed from its header.
+var ErrBlockIDMismatch = errors.New("block ID mismatch")
+
+// ErrPayloadHashMismatch is returned when a block payload doesn't match the payload hash of its header.
+var ErrPayloadHashMismatch = errors.New("block payload hash mismatch")
+
+// ComputeID computes the ID of the block from its header fields, using the given encoding version.
+func (h BlockHeader) ComputeID(version BlockEncodingVersion) Identifier {
+	return HashToID(defaultEntityHasher.ComputeHash(h.Encode(version)))
+}
+
+// Encode returns the canonical RLP byte representation of the block header, used to compute its ID.
+func (h BlockHeader) Encode(version BlockEncodingVersion) []byte {
+	timestamp := uint64(h.Timestamp.UnixMilli())
+	if version == BlockEncodingLegacy {
+		timestamp = uint64(h.Timestamp.UnixNano())
+	}
+
+	temp := struct {
+		ChainID            string
+		ParentID           Identifier
+		Height             uint64
+		PayloadHash        Identifier
+		Timestamp          uint64
+		View               uint64
+		ParentView         uint64
+		ParentVoterIndices []byte
+		ParentVoterSigData []byte
+		ProposerID         Identifier
+		LastViewTCID       Identifier
+	}{
+		ChainID:            string(bytes.TrimRight(h.ChainID[:], "\x00")),
+		ParentID:           h.ParentID,
+		Height:             h.Height,
+		PayloadHash:        BytesToID(h.PayloadHash),
+		Timestamp:          timestamp,
+		View:               h.View,
+		ParentView:         h.ParentView,
+		ParentVoterIndices: h.ParentVoterIndices,
+		ParentVoterSigData: h.ParentVoterSigData,
+		ProposerID:         h.ProposerID,
+		LastViewTCID:       h.LastViewTimeoutCertificate.computeID(version),
+	}
+	return mustRLPEncode(&temp)
+}
+
+// CheckID checks that the ID of the block matches the ID computed from its header fields,
+// and returns the encoding version producing the ID.
+//
+// The error returned for a mismatching ID wraps ErrBlockIDMismatch.
+func (h BlockHeader) CheckID() (BlockEncodingVersion, error) {
+	for _, version := range blockEncodingVersions {
+		if h.ComputeID(version) == h.ID {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: block %s doesn't match its header fields", ErrBlockIDMismatch, h.ID)
+}
+
+// computeID returns the ID of the timeout certificate, or an empty ID if the certificate is absent.
+func (tc TimeoutCertificate) computeID(version BlockEncodingVersion) Identifier {
+	if tc.View == 0 && len(tc.SigData) == 0 {
+		return EmptyID
+	}
+
+	if version == BlockEncodingLegacy {
+		temp := struct {
+			View          uint64
+			NewestQCViews []uint64
+			NewestQCID    Identifier
+			SignerIndices []byte
+			SigData       []byte
+		}{
+			View:          tc.View,
+			NewestQCViews: tc.HighQCViews,
+			NewestQCID:    HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&tc.HighestQC))),
+			SignerIndices: tc.SignerIndices,
+			SigData:       tc.SigData,
+		}
+		return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&temp)))
+	}
+
+	temp := struct {
+		View          uint64
+		NewestQCViews []uint64
+		NewestQC      QuorumCertificate
+		SignerIndices []byte
+		SigData       []byte
+	}{
+		View:          tc.View,
+		NewestQCViews: tc.HighQCViews,
+		NewestQC:      tc.HighestQC,
+		SignerIndices: tc.SignerIndices,
+		SigData:       tc.SigData,
+	}
+	return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&temp)))
+}
+
+// ComputeHash computes the payload hash committed to by the block header, using the given
+// encoding version.
+//
+// The payload hash commits to the collection guarantees, seals, execution receipts, execution
+// results and protocol state ID of the payload.
+func (p BlockPayload) ComputeHash(version BlockEncodingVersion) (Identifier, error) {
+	guaranteeIDs := make([]Identifier, len(p.CollectionGuarantees))
+	for i, guarantee := range p.CollectionGuarantees {
+		guaranteeIDs[i] = guarantee.computeID(version)
+	}
+
+	sealIDs := make([]Identifier, len(p.Seals))
+	for i, seal := range p.Seals {
+		sealIDs[i] = seal.ID()
+	}
+
+	receiptIDs := make([]Identifier, len(p.ExecutionReceiptMetaList))
+	for i, receipt := range p.ExecutionReceiptMetaList {
+		receiptIDs[i] = receipt.computeID(version)
+	}
+
+	resultIDs := make([]Identifier, len(p.ExecutionResultsList))
+	for i, result := range p.ExecutionResultsList {
+		id, err := result.ID()
+		if err != nil {
+			return EmptyID, fmt.Errorf("failed to compute ID of execution result %d: %w", i, err)
+		}
+		resultIDs[i] = id
+	}
+
+	return concatSum(
+		MerkleRoot(guaranteeIDs...),
+		MerkleRoot(sealIDs...),
+		MerkleRoot(receiptIDs...),
+		MerkleRoot(resultIDs...),
+		p.ProtocolStateID,
+	), nil
+}
+
+// CheckIntegrity checks that the block ID matches its header fields, and that the payload
+// matches the payload hash of the header.
+//
+// The errors returned wrap ErrBlockIDMismatch or ErrPayloadHashMismatch.
+func (b Block) CheckIntegrity() error {
+	version, err := b.BlockHeader.CheckID()
+	if err != nil {
+		return err
+	}
+
+	hash, err := b.BlockPayload.ComputeHash(version)
+	if err != nil {
+		return err
+	}
+	if hash != BytesToID(b.PayloadHash) {
+		return fmt.Errorf("%w: block %s commits to %x, computed %s", ErrPayloadHashMismatch, b.ID, b.PayloadHash, hash)
+	}
+	return nil
+}
+
+// computeID returns the ID of the guarantee. Legacy guarantees are identified by their collection ID.
+func (g CollectionGuarantee) computeID(version BlockEncodingVersion) Identifier {
+	if version == BlockEncodingLegacy {
+		return g.CollectionID
+	}
+
+	temp := struct {
+		CollectionID     Identifier
+		ReferenceBlockID Identifier
+		ClusterChainID   string
+		SignerIndices    []byte
+		Signature        []byte
+	}{
+		CollectionID:     g.CollectionID,
+		ReferenceBlockID: g.ReferenceBlockID,
+		ClusterChainID:   string(g.ClusterChainID),
+		SignerIndices:    g.SignerIndices,
+		Signature:        g.Signature,
+	}
+	return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&temp)))
+}
+
+type aggregatedSignatureCanonicalForm struct {
+	VerifierSignatures [][]byte
+	SignerIDs          []Identifier
+}
+
+// ID returns the ID of the seal, as committed to by the payload of the sealing block.
+//
+// The ID is the same for all block encoding versions.
+func (s BlockSeal) ID() Identifier {
+	sigs := make([]aggregatedSignatureCanonicalForm, len(s.AggregatedApprovalSigs))
+	for i, sig := range s.AggregatedApprovalSigs {
+		sigs[i] = aggregatedSignatureCanonicalForm{
+			VerifierSignatures: sig.VerifierSignatures,
+			SignerIDs:          sig.SignerIds,
+		}
+	}
+
+	temp := struct {
+		BlockID                Identifier
+		ResultID               Identifier
+		FinalState             []byte
+		AggregatedApprovalSigs []aggregatedSignatureCanonicalForm
+	}{
+		BlockID:                s.BlockID,
+		ResultID:               s.ResultId,
+		FinalState:             s.FinalState,
+		AggregatedApprovalSigs: sigs,
+	}
+	return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&temp)))
+}
+
+type receiptBodyCanonicalForm struct {
+	ExecutorID Identifier
+	ResultID   Identifier
+	Spocks     [][]byte
+}
+
+// computeID returns the ID of the receipt, which is identical to the ID of the full receipt.
+func (r ExecutionReceiptMeta) computeID(version BlockEncodingVersion) Identifier {
+	body := receiptBodyCanonicalForm{
+		ExecutorID: r.ExecutorID,
+		ResultID:   r.ResultID,
+		Spocks:     r.Spocks,
+	}
+	if version == BlockEncodingLegacy {
+		return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&body)))
+	}
+
+	temp := struct {
+		Body              receiptBodyCanonicalForm
+		ExecutorSignature []byte
+	}{
+		Body:              body,
+		ExecutorSignature: r.ExecutorSignature,
+	}
+	return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&temp)))
+}
@@ -0,0 +1,175 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// testID returns an identifier whose bytes are consecutive, starting at the given byte.
+func testID(start byte) flow.Identifier {
+	var id flow.Identifier
+	for i := range id {
+		id[i] = start + byte(i)
+	}
+	return id
+}
+
+// testBlock returns a block whose IDs were computed with flow-go, for the current encoding.
+func testBlock() flow.Block {
+	return flow.Block{
+		BlockHeader: flow.BlockHeader{
+			ID:                 flow.HexToID("bc0ee85b664455501e92fcc14f56f42cedc9418a4ed6cd0e9ef2f4c6651c4477"),
+			ParentID:           testID(1),
+			Height:             1000,
+			Timestamp:          time.Date(2025, 10, 1, 12, 0, 0, 123456789, time.UTC),
+			PayloadHash:        flow.HexToID("f8a977d568442929937f29b1dba1847a2b8fb41cd1d3d2f1f36030878c870829").Bytes(),
+			View:               43,
+			ParentView:         41,
+			ParentVoterIndices: []byte{0xff, 0x80},
+			ParentVoterSigData: []byte{4, 5, 6},
+			ProposerID:         testID(2),
+			ChainID:            flow.HashToID([]byte(flow.Mainnet)),
+			LastViewTimeoutCertificate: flow.TimeoutCertificate{
+				View:        42,
+				HighQCViews: []uint64{41, 40},
+				HighestQC: flow.QuorumCertificate{
+					View:          41,
+					BlockID:       testID(9),
+					SignerIndices: []byte{0xf0},
+					SigData:       []byte{1, 2, 3},
+				},
+				SignerIndices: []byte{0xc0},
+				SigData:       []byte{7, 8},
+			},
+		},
+		BlockPayload: flow.BlockPayload{
+			CollectionGuarantees: []*flow.CollectionGuarantee{
+				{
+					CollectionID:     testID(10),
+					ReferenceBlockID: testID(11),
+					ClusterChainID:   "cluster-0",
+					SignerIndices:    []byte{1},
+					Signature:        []byte{2, 3},
+				},
+				{
+					CollectionID:     testID(12),
+					ReferenceBlockID: testID(11),
+					ClusterChainID:   "cluster-1",
+					SignerIndices:    []byte{4},
+					Signature:        []byte{5},
+				},
+			},
+			Seals: []*flow.BlockSeal{{
+				BlockID:    testID(20),
+				ResultId:   testID(21),
+				FinalState: testID(22).Bytes(),
+				AggregatedApprovalSigs: []*flow.AggregatedSignature{{
+					VerifierSignatures: [][]byte{{1}, {2}},
+					SignerIds:          []flow.Identifier{testID(23), testID(24)},
+				}},
+			}},
+			ExecutionReceiptMetaList: []*flow.ExecutionReceiptMeta{{
+				ExecutorID:        testID(30),
+				ResultID:          testID(31),
+				Spocks:            [][]byte{{9}},
+				ExecutorSignature: []byte{8},
+			}},
+			ProtocolStateID: testID(40),
+		},
+	}
+}
+
+func TestBlockHeader_ComputeID(t *testing.T) {
+	block := testBlock()
+
+	assert.Equal(t, block.ID, block.ComputeID(flow.BlockEncodingCurrent))
+	assert.Equal(t,
+		flow.HexToID("f258d4c8c4a02ae93c8940d0bcd93cc6ad564a1989c1b3b4804a5374021c2874"),
+		block.ComputeID(flow.BlockEncodingLegacy),
+	)
+
+	block.LastViewTimeoutCertificate = flow.TimeoutCertificate{}
+	assert.Equal(t,
+		flow.HexToID("f26bc8f91eec82c2cc95fa34bb776412da7238805c98825f612a29993c68fb9b"),
+		block.ComputeID(flow.BlockEncodingCurrent),
+	)
+}
+
+func TestBlockHeader_CheckID(t *testing.T) {
+	block := testBlock()
+
+	version, err := block.CheckID()
+	require.NoError(t, err)
+	assert.Equal(t, flow.BlockEncodingCurrent, version)
+
+	block.ID = flow.HexToID("f258d4c8c4a02ae93c8940d0bcd93cc6ad564a1989c1b3b4804a5374021c2874")
+	version, err = block.CheckID()
+	require.NoError(t, err)
+	assert.Equal(t, flow.BlockEncodingLegacy, version)
+
+	block.Height++
+	_, err = block.CheckID()
+	assert.ErrorIs(t, err, flow.ErrBlockIDMismatch)
+}
+
+func TestBlockPayload_ComputeHash(t *testing.T) {
+	block := testBlock()
+
+	hash, err := block.BlockPayload.ComputeHash(flow.BlockEncodingCurrent)
+	require.NoError(t, err)
+	assert.Equal(t, flow.BytesToID(block.PayloadHash), hash)
+
+	hash, err = block.BlockPayload.ComputeHash(flow.BlockEncodingLegacy)
+	require.NoError(t, err)
+	assert.Equal(t, flow.HexToID("e8afbeacd83d683f6abd4112f02925e5b3d35812182818e974e4292e00ab82d6"), hash)
+
+	hash, err = flow.BlockPayload{ProtocolStateID: testID(40)}.ComputeHash(flow.BlockEncodingCurrent)
+	require.NoError(t, err)
+	assert.Equal(t, flow.HexToID("2f8a5872f1d2303627102c3171cc1f1466f2a56ca050fd411ba716d8f5bab20c"), hash)
+}
+
+func TestBlock_CheckIntegrity(t *testing.T) {
+	block := testBlock()
+	require.NoError(t, block.CheckIntegrity())
+
+	block.CollectionGuarantees = block.CollectionGuarantees[:1]
+	assert.ErrorIs(t, block.CheckIntegrity(), flow.ErrPayloadHashMismatch)
+}
+
+func TestMerkleRoot(t *testing.T) {
+	assert.Equal(t,
+		flow.HexToID("0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8"),
+		flow.MerkleRoot(),
+	)
+	assert.Equal(t,
+		flow.HexToID("9d414f9adf6a482bb8dd5b60f7dd71c9d9a6ab4878d6da3a22b85fcd9ce72b30"),
+		flow.MerkleRoot(testID(5)),
+	)
+	assert.Equal(t,
+		flow.HexToID("116decdb2a541e6a180069c80c8313e7730282d5c5be975034db3fb47057ea1b"),
+		flow.MerkleRoot(testID(1), testID(200), testID(3), testID(1)),
+	)
+}
@@ -49,6 +49,8 @@ type CollectionGuarantee struct {
 	ReferenceBlockID Identifier
 	Signature        []byte
 	SignerIndices    []byte
+	// ClusterChainID is the chain ID of the collector cluster which guaranteed the collection.
+	ClusterChainID ChainID
 }
 
 type FullCollection struct {
@@ -0,0 +1,90 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow
+
+// CollectionMismatch is a collection found at the position of the guarantee of another collection.
+type CollectionMismatch struct {
+	// Index is the position of the guarantee in the block, and of the collection in the verified list.
+	Index int
+	// GuaranteedID is the ID of the collection guaranteed at this position.
+	GuaranteedID Identifier
+	// CollectionID is the ID computed from the transactions of the collection at this position.
+	CollectionID Identifier
+}
+
+// CollectionVerificationResult is the result of the verification of the collections of a block.
+type CollectionVerificationResult struct {
+	// Missing lists the IDs of the guaranteed collections not found in the verified collections.
+	Missing []Identifier
+	// Extra lists the IDs of the verified collections not guaranteed by the block,
+	// including collections listed more than once.
+	Extra []Identifier
+	// Mismatched lists the positions where the verified collection isn't the guaranteed one,
+	// because the collection contents differ or the collections are out of order.
+	Mismatched []CollectionMismatch
+}
+
+// Valid returns true if the verified collections are exactly the collections guaranteed by the block, in order.
+func (r CollectionVerificationResult) Valid() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Mismatched) == 0
+}
+
+// VerifyBlockCollections checks that the given collections are the collections guaranteed by the
+// block, in the order of the guarantees.
+//
+// The ID of each collection is computed from the IDs of its transactions, so a collection with
+// a dropped, added or modified transaction doesn't match its guarantee. The guarantees themselves
+// are only trusted if the block was checked with Block.CheckIntegrity.
+func VerifyBlockCollections(block *Block, collections []*FullCollection) CollectionVerificationResult {
+	var result CollectionVerificationResult
+
+	guaranteed := make(map[Identifier]bool, len(block.CollectionGuarantees))
+	for _, guarantee := range block.CollectionGuarantees {
+		guaranteed[guarantee.CollectionID] = true
+	}
+
+	found := make(map[Identifier]bool, len(collections))
+	for i, collection := range collections {
+		if collection == nil {
+			continue
+		}
+
+		id := collection.ID()
+		if !guaranteed[id] || found[id] {
+			result.Extra = append(result.Extra, id)
+		}
+		found[id] = true
+
+		if i < len(block.CollectionGuarantees) && block.CollectionGuarantees[i].CollectionID != id {
+			result.Mismatched = append(result.Mismatched, CollectionMismatch{
+				Index:        i,
+				GuaranteedID: block.CollectionGuarantees[i].CollectionID,
+				CollectionID: id,
+			})
+		}
+	}
+
+	for _, guarantee := range block.CollectionGuarantees {
+		if !found[guarantee.CollectionID] {
+			result.Missing = append(result.Missing, guarantee.CollectionID)
+		}
+	}
+
+	return result
+}
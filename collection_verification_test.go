@@ -0,0 +1,94 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+func newTestCollection(name string, size int) *flow.FullCollection {
+	collection := &flow.FullCollection{}
+	for i := 0; i < size; i++ {
+		script := fmt.Sprintf("transaction { execute { log(\"%s-%d\") } }", name, i)
+		collection.Transactions = append(collection.Transactions, flow.NewTransaction().SetScript([]byte(script)))
+	}
+	return collection
+}
+
+func TestVerifyBlockCollections(t *testing.T) {
+	first := newTestCollection("first", 2)
+	second := newTestCollection("second", 3)
+
+	block := &flow.Block{
+		BlockPayload: flow.BlockPayload{
+			CollectionGuarantees: []*flow.CollectionGuarantee{
+				{CollectionID: first.ID()},
+				{CollectionID: second.ID()},
+			},
+		},
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		result := flow.VerifyBlockCollections(block, []*flow.FullCollection{first, second})
+		assert.True(t, result.Valid())
+	})
+
+	t.Run("Dropped transaction", func(t *testing.T) {
+		dropped := &flow.FullCollection{Transactions: second.Transactions[1:]}
+
+		result := flow.VerifyBlockCollections(block, []*flow.FullCollection{first, dropped})
+		assert.False(t, result.Valid())
+		assert.Equal(t, []flow.Identifier{second.ID()}, result.Missing)
+		assert.Equal(t, []flow.Identifier{dropped.ID()}, result.Extra)
+		assert.Equal(t, []flow.CollectionMismatch{{
+			Index:        1,
+			GuaranteedID: second.ID(),
+			CollectionID: dropped.ID(),
+		}}, result.Mismatched)
+	})
+
+	t.Run("Out of order", func(t *testing.T) {
+		result := flow.VerifyBlockCollections(block, []*flow.FullCollection{second, first})
+		assert.False(t, result.Valid())
+		assert.Empty(t, result.Missing)
+		assert.Empty(t, result.Extra)
+		assert.Len(t, result.Mismatched, 2)
+	})
+
+	t.Run("Missing collection", func(t *testing.T) {
+		result := flow.VerifyBlockCollections(block, []*flow.FullCollection{first})
+		assert.Equal(t, []flow.Identifier{second.ID()}, result.Missing)
+		assert.Empty(t, result.Extra)
+		assert.Empty(t, result.Mismatched)
+	})
+
+	t.Run("Extra collection", func(t *testing.T) {
+		extra := newTestCollection("extra", 1)
+
+		result := flow.VerifyBlockCollections(block, []*flow.FullCollection{first, second, extra, first})
+		assert.Empty(t, result.Missing)
+		assert.Equal(t, []flow.Identifier{extra.ID(), first.ID()}, result.Extra)
+		assert.Empty(t, result.Mismatched)
+	})
+}
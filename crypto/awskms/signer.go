@@ -29,9 +29,9 @@ import (
 	"github.com/onflow/flow-go-sdk/crypto/internal"
 )
 
-var _ crypto.Signer = (*Signer)(nil)
+var _ crypto.ContextSigner = (*Signer)(nil)
 
-// Signer is a AWS KMS implementation of crypto.Signer.
+// Signer is a AWS KMS implementation of crypto.Signer and crypto.ContextSigner.
 type Signer struct {
 	ctx    context.Context
 	client *kms.Client
@@ -69,8 +69,18 @@ func (c *Client) SignerForKey(
 
 // Sign signs the given message using the KMS signing key for this signer.
 //
-// Reference: https://github.com/aws/aws-sdk-go-v2/blob/main/service/kms/api_op_Sign.go
+// The KMS request is bound to the context passed to `SignerForKey`.
+// Use `SignContext` to bind each request to its own context.
 func (s *Signer) Sign(message []byte) ([]byte, error) {
+	return s.SignContext(s.ctx, message)
+}
+
+// SignContext signs the given message using the KMS signing key for this signer.
+//
+// The KMS request is cancelled if the context is done before it completes.
+//
+// Reference: https://github.com/aws/aws-sdk-go-v2/blob/main/service/kms/api_op_Sign.go
+func (s *Signer) SignContext(ctx context.Context, message []byte) ([]byte, error) {
 
 	keyArn := s.key.ARN()
 	// AWS KMS supports signing messages without pre-hashing
@@ -100,7 +110,7 @@ func (s *Signer) Sign(message []byte) ([]byte, error) {
 			MessageType:      types.MessageTypeDigest,
 		}
 	}
-	result, err := s.client.Sign(s.ctx, request)
+	result, err := s.client.Sign(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("awskms: failed to sign: %w", err)
 	}
@@ -0,0 +1,275 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package azurekv provides an Azure Key Vault
+// implementation of the crypto.Signer interface.
+//
+// The documentation for Azure Key Vault keys can be found here: https://learn.microsoft.com/en-us/rest/api/keyvault/keys
+package azurekv
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+const (
+	resourceIDFormat = "%s/keys/%s/%s"
+	apiVersion       = "7.4"
+)
+
+// Key is a reference to an Azure Key Vault asymmetric signing key version.
+type Key struct {
+	// VaultURL is the URL of the key vault, for instance "https://my-vault.vault.azure.net".
+	VaultURL   string `json:"vaultUrl"`
+	Name       string `json:"name"`
+	KeyVersion string `json:"keyVersion"`
+}
+
+// ResourceID returns the key identifier of this key version.
+//
+// Ref: https://learn.microsoft.com/en-us/azure/key-vault/general/about-keys-secrets-certificates#object-identifiers
+func (k Key) ResourceID() string {
+	return fmt.Sprintf(
+		resourceIDFormat,
+		strings.TrimRight(k.VaultURL, "/"),
+		k.Name,
+		k.KeyVersion,
+	)
+}
+
+// KeyFromResourceID returns a `Key` from a key identifier.
+//
+// Example key identifier format: "https://my-vault.vault.azure.net/keys/my-key/78deebed173b48e48f55abf87ed4cf71"
+func KeyFromResourceID(resourceID string) (Key, error) {
+	key := Key{}
+
+	u, err := url.Parse(resourceID)
+	if err != nil {
+		return key, fmt.Errorf("azurekv: failed to parse key identifier %s: %w", resourceID, err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Scheme == "" || u.Host == "" || len(parts) != 3 || parts[0] != "keys" || parts[1] == "" || parts[2] == "" {
+		return key, fmt.Errorf("azurekv: wrong format for the key identifier: %s", resourceID)
+	}
+
+	key.VaultURL = u.Scheme + "://" + u.Host
+	key.Name, key.KeyVersion = parts[1], parts[2]
+
+	return key, nil
+}
+
+// TokenFunc returns a bearer access token for the Azure Key Vault resource.
+//
+// It is called before each request and is expected to cache and refresh tokens,
+// for instance by wrapping an `azidentity` credential.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// Client is a client for interacting with the Azure Key Vault API
+// using types native to the Flow Go SDK.
+type Client struct {
+	token      TokenFunc
+	httpClient *http.Client
+}
+
+// ClientOption is a configuration option for the client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests to Azure Key Vault.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient creates a new Azure Key Vault client authenticated with the given tokens.
+func NewClient(token TokenFunc, opts ...ClientOption) (*Client, error) {
+	if token == nil {
+		return nil, fmt.Errorf("azurekv: missing token function")
+	}
+
+	client := &Client{
+		token:      token,
+		httpClient: http.DefaultClient,
+	}
+	for _, apply := range opts {
+		apply(client)
+	}
+
+	return client, nil
+}
+
+// jsonWebKey is the public part of a Key Vault key, in the JSON Web Key format.
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+type keyBundle struct {
+	Key jsonWebKey `json:"key"`
+}
+
+// GetPublicKey fetches the public key portion of a Key Vault asymmetric signing key version.
+//
+// Key Vault keys of the type `EC` on the curves `P-256` and `P-256K`
+// are the only keys supported by the SDK.
+//
+// Ref: https://learn.microsoft.com/en-us/rest/api/keyvault/keys/get-key/get-key
+func (c *Client) GetPublicKey(ctx context.Context, key Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+	var result keyBundle
+	err := c.do(ctx, http.MethodGet, key.ResourceID(), nil, &result)
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("azurekv: failed to fetch public key from Key Vault API: %w", err)
+	}
+
+	sigAlgo := parseSignatureAlgorithm(result.Key)
+	if sigAlgo == crypto.UnknownSignatureAlgorithm {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf(
+				"azurekv: unsupported signature algorithm %s %s",
+				result.Key.KeyType,
+				result.Key.Curve,
+			)
+	}
+
+	hashAlgo := parseHashAlgorithm(result.Key)
+	if hashAlgo == crypto.UnknownHashAlgorithm {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf(
+				"azurekv: unsupported hash algorithm %s %s",
+				result.Key.KeyType,
+				result.Key.Curve,
+			)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(result.Key.X)
+	if err != nil {
+		return nil, crypto.UnknownHashAlgorithm, fmt.Errorf("azurekv: failed to parse public key: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(result.Key.Y)
+	if err != nil {
+		return nil, crypto.UnknownHashAlgorithm, fmt.Errorf("azurekv: failed to parse public key: %w", err)
+	}
+	if len(x) > 32 || len(y) > 32 {
+		return nil, crypto.UnknownHashAlgorithm, fmt.Errorf("azurekv: failed to parse public key: invalid coordinates length")
+	}
+
+	// left pad the coordinates with zeroes
+	point := make([]byte, 64)
+	copy(point[32-len(x):], x)
+	copy(point[64-len(y):], y)
+
+	publicKey, err := crypto.DecodePublicKey(sigAlgo, point)
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("azurekv: failed to decode public key: %w", err)
+	}
+
+	return publicKey, hashAlgo, nil
+}
+
+// errorResponse is the body of Key Vault error responses.
+type errorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// do sends a request to the Key Vault API and decodes the JSON response into result.
+func (c *Client) do(ctx context.Context, method string, resourceURL string, body any, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, resourceURL+"?api-version="+apiVersion, reqBody)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var errRes errorResponse
+		_ = json.NewDecoder(res.Body).Decode(&errRes)
+		return fmt.Errorf("unexpected status %d: %s %s", res.StatusCode, errRes.Error.Code, errRes.Error.Message)
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// parseSignatureAlgorithm returns the `SignatureAlgorithm` corresponding to the input Key Vault key type.
+func parseSignatureAlgorithm(key jsonWebKey) crypto.SignatureAlgorithm {
+	// HSM protected keys have the type EC-HSM
+	if key.KeyType != "EC" && key.KeyType != "EC-HSM" {
+		return crypto.UnknownSignatureAlgorithm
+	}
+
+	if key.Curve == "P-256" {
+		return crypto.ECDSA_P256
+	}
+
+	if key.Curve == "P-256K" {
+		return crypto.ECDSA_secp256k1
+	}
+
+	return crypto.UnknownSignatureAlgorithm
+}
+
+// parseHashAlgorithm returns the `HashAlgorithm` corresponding to the input Key Vault key type.
+func parseHashAlgorithm(key jsonWebKey) crypto.HashAlgorithm {
+	if key.Curve == "P-256" || key.Curve == "P-256K" {
+		return crypto.SHA2_256
+	}
+
+	return crypto.UnknownHashAlgorithm
+}
@@ -0,0 +1,213 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package azurekv_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/azurekv"
+)
+
+func TestKeyFromResourceID(t *testing.T) {
+	key := azurekv.Key{
+		VaultURL:   "https://my-vault.vault.azure.net",
+		Name:       "flow-account",
+		KeyVersion: "78deebed173b48e48f55abf87ed4cf71",
+	}
+
+	resourceID := key.ResourceID()
+
+	assert.Equal(t, resourceID, "https://my-vault.vault.azure.net/keys/flow-account/78deebed173b48e48f55abf87ed4cf71")
+
+	keyFromResourceID, err := azurekv.KeyFromResourceID(resourceID)
+	require.NoError(t, err)
+
+	assert.Equal(t, key, keyFromResourceID)
+
+	for _, id := range []string{
+		"",
+		"my-vault.vault.azure.net/keys/flow-account/1",
+		"https://my-vault.vault.azure.net/keys/flow-account",
+		"https://my-vault.vault.azure.net/secrets/flow-account/1",
+	} {
+		_, err = azurekv.KeyFromResourceID(id)
+		assert.Error(t, err, id)
+	}
+}
+
+// prehashedHasher is a hasher returning its input, used to sign digests.
+type prehashedHasher struct {
+	crypto.Hasher
+}
+
+func (prehashedHasher) ComputeHash(digest []byte) crypto.Hash {
+	return digest
+}
+
+// newKeyVaultServer returns a local stand-in of the Key Vault API holding a single key.
+func newKeyVaultServer(t *testing.T, curve string, alg string, sk crypto.PrivateKey) *httptest.Server {
+	mux := http.NewServeMux()
+
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":"Unauthorized","message":"invalid token"}}`))
+			return false
+		}
+		require.Equal(t, "7.4", r.URL.Query().Get("api-version"))
+		return true
+	}
+
+	mux.HandleFunc("GET /keys/flow-account/1", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		point := sk.PublicKey().Encode()
+		res := map[string]any{
+			"key": map[string]any{
+				"kid": "flow-account/1",
+				"kty": "EC",
+				"crv": curve,
+				"x":   base64.RawURLEncoding.EncodeToString(point[:32]),
+				"y":   base64.RawURLEncoding.EncodeToString(point[32:]),
+			},
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+
+	mux.HandleFunc("POST /keys/flow-account/1/sign", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		var req struct {
+			Alg   string `json:"alg"`
+			Value string `json:"value"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, alg, req.Alg)
+
+		digest, err := base64.RawURLEncoding.DecodeString(req.Value)
+		require.NoError(t, err)
+		require.Len(t, digest, 32)
+
+		sig, err := sk.Sign(digest, prehashedHasher{crypto.NewSHA2_256()})
+		require.NoError(t, err)
+
+		res := map[string]any{
+			"kid":   "flow-account/1",
+			"value": base64.RawURLEncoding.EncodeToString(sig),
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func staticToken(token string) azurekv.TokenFunc {
+	return func(context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+func TestSigning(t *testing.T) {
+	curves := []struct {
+		sigAlgo crypto.SignatureAlgorithm
+		curve   string
+		alg     string
+	}{
+		{crypto.ECDSA_P256, "P-256", "ES256"},
+		{crypto.ECDSA_secp256k1, "P-256K", "ES256K"},
+	}
+
+	for _, c := range curves {
+		t.Run(c.sigAlgo.String(), func(t *testing.T) {
+			seed := make([]byte, crypto.MinSeedLength)
+			_, err := rand.Read(seed)
+			require.NoError(t, err)
+			sk, err := crypto.GeneratePrivateKey(c.sigAlgo, seed)
+			require.NoError(t, err)
+
+			server := newKeyVaultServer(t, c.curve, c.alg, sk)
+			defer server.Close()
+
+			ctx := context.Background()
+			client, err := azurekv.NewClient(staticToken("test-token"))
+			require.NoError(t, err)
+
+			key, err := azurekv.KeyFromResourceID(server.URL + "/keys/flow-account/1")
+			require.NoError(t, err)
+
+			pk, hashAlgo, err := client.GetPublicKey(ctx, key)
+			require.NoError(t, err)
+			assert.True(t, sk.PublicKey().Equals(pk))
+			assert.Equal(t, crypto.SHA2_256, hashAlgo)
+
+			signer, err := client.SignerForKey(ctx, key)
+			require.NoError(t, err)
+
+			msg := []byte("random_message")
+			sig, err := signer.Sign(msg)
+			require.NoError(t, err)
+
+			valid, err := pk.Verify(sig, msg, crypto.NewSHA2_256())
+			require.NoError(t, err)
+			assert.True(t, valid)
+		})
+	}
+
+	t.Run("Unsupported curve", func(t *testing.T) {
+		seed := make([]byte, crypto.MinSeedLength)
+		sk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, seed)
+		require.NoError(t, err)
+
+		server := newKeyVaultServer(t, "P-384", "ES384", sk)
+		defer server.Close()
+
+		client, err := azurekv.NewClient(staticToken("test-token"))
+		require.NoError(t, err)
+
+		_, err = client.SignerForKey(context.Background(), azurekv.Key{VaultURL: server.URL, Name: "flow-account", KeyVersion: "1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		seed := make([]byte, crypto.MinSeedLength)
+		sk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, seed)
+		require.NoError(t, err)
+
+		server := newKeyVaultServer(t, "P-256", "ES256", sk)
+		defer server.Close()
+
+		client, err := azurekv.NewClient(staticToken("wrong-token"))
+		require.NoError(t, err)
+
+		_, _, err = client.GetPublicKey(context.Background(), azurekv.Key{VaultURL: server.URL, Name: "flow-account", KeyVersion: "1"})
+		assert.ErrorContains(t, err, "invalid token")
+	})
+}
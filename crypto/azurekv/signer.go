@@ -0,0 +1,147 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package azurekv
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/internal"
+)
+
+var _ crypto.ContextSigner = (*Signer)(nil)
+
+// Signer is an Azure Key Vault implementation of crypto.Signer and crypto.ContextSigner.
+type Signer struct {
+	ctx    context.Context
+	client *Client
+	key    Key
+	// ECDSA is the only algorithm supported by this package. The signature algorithm
+	// therefore represents the elliptic curve used. The curve is needed to parse the Key Vault signature.
+	curve crypto.SignatureAlgorithm
+	// public key for easier access
+	publicKey crypto.PublicKey
+	// Hash algorithm associated to the Key Vault signing key
+	hashAlgo crypto.HashAlgorithm
+}
+
+// SignerForKey returns a new Azure Key Vault signer for an asymmetric signing key version.
+//
+// Only ECDSA keys on P-256 and secp256k1 curves and SHA2-256 are supported.
+func (c *Client) SignerForKey(
+	ctx context.Context,
+	key Key,
+) (*Signer, error) {
+	pk, hashAlgo, err := c.GetPublicKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		ctx:       ctx,
+		client:    c,
+		key:       key,
+		curve:     pk.Algorithm(),
+		publicKey: pk,
+		hashAlgo:  hashAlgo,
+	}, nil
+}
+
+type signRequest struct {
+	Algorithm string `json:"alg"`
+	Value     string `json:"value"`
+}
+
+type signResponse struct {
+	Value string `json:"value"`
+}
+
+// Sign signs the given message using the Key Vault signing key for this signer.
+//
+// The Key Vault request is bound to the context passed to `SignerForKey`.
+// Use `SignContext` to bind each request to its own context.
+func (s *Signer) Sign(message []byte) ([]byte, error) {
+	return s.SignContext(s.ctx, message)
+}
+
+// SignContext signs the given message using the Key Vault signing key for this signer.
+//
+// Key Vault only signs digests, the message is always hashed outside Key Vault.
+// The request is cancelled if the context is done before it completes.
+//
+// Reference: https://learn.microsoft.com/en-us/rest/api/keyvault/keys/sign/sign
+func (s *Signer) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	algorithm, err := signingAlgorithm(s.curve, s.hashAlgo)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: failed to sign: %w", err)
+	}
+
+	// this is guaranteed to only return supported hash algos by Key Vault,
+	// since `s.hashAlgo` has been checked when the signer object was created.
+	hasher, err := crypto.NewHasher(s.hashAlgo)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: failed to sign: %w", err)
+	}
+	digest := hasher.ComputeHash(message)
+
+	request := signRequest{
+		Algorithm: algorithm,
+		Value:     base64.RawURLEncoding.EncodeToString(digest),
+	}
+
+	var result signResponse
+	err = s.client.do(ctx, http.MethodPost, s.key.ResourceID()+"/sign", request, &result)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: failed to sign: %w", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(result.Value)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: failed to parse signature: %w", err)
+	}
+	sig, err = internal.NormalizeECDSASignature(sig, s.curve)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: failed to parse signature: %w", err)
+	}
+	return sig, nil
+}
+
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.publicKey
+}
+
+// returns the Key Vault signing algorithm for the curve and hashing algorithm.
+// This function only covers algorithms supported by Key Vault.
+func signingAlgorithm(curve crypto.SignatureAlgorithm, hashAlgo crypto.HashAlgorithm) (string, error) {
+	if hashAlgo != crypto.SHA2_256 {
+		return "", fmt.Errorf("unsupported hash algorithm %s", hashAlgo)
+	}
+
+	switch curve {
+	case crypto.ECDSA_P256:
+		return "ES256", nil
+	case crypto.ECDSA_secp256k1:
+		return "ES256K", nil
+	default:
+		return "", fmt.Errorf("unsupported signature algorithm %s", curve)
+	}
+}
@@ -19,6 +19,7 @@
 package crypto
 
 import (
+	"context"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/hex"
@@ -93,6 +94,81 @@ type Signer interface {
 	PublicKey() PublicKey
 }
 
+// A ContextSigner is a Signer that accepts a context when generating signatures.
+//
+// Signers backed by remote services (for instance a KMS) should implement this interface
+// so that signing requests can be cancelled or bound to a deadline.
+type ContextSigner interface {
+	Signer
+	// SignContext signs the given message with this signer.
+	//
+	// The call should return early with the context error if the context
+	// is cancelled or its deadline is exceeded.
+	SignContext(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// NewContextSigner returns a ContextSigner for the given signer.
+//
+// If the signer already implements ContextSigner, it is returned as is.
+// Otherwise, the signer is wrapped so that `SignContext` returns as soon as the context
+// is done, even if the underlying `Sign` call has not returned yet.
+func NewContextSigner(signer Signer) ContextSigner {
+	if s, ok := signer.(ContextSigner); ok {
+		return s
+	}
+	return contextSigner{signer}
+}
+
+// contextSigner adapts a Signer to the ContextSigner interface.
+type contextSigner struct {
+	Signer
+}
+
+var _ ContextSigner = contextSigner{}
+
+func (s contextSigner) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// the context can never be done, sign synchronously
+	if ctx.Done() == nil {
+		return s.Sign(message)
+	}
+
+	type result struct {
+		sig []byte
+		err error
+	}
+
+	// the channel is buffered so that the signing goroutine
+	// does not leak if the context is done first
+	resultChan := make(chan result, 1)
+	go func() {
+		sig, err := s.Sign(message)
+		resultChan <- result{sig, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-resultChan:
+		return r.sig, r.err
+	}
+}
+
+// A BatchSigner is a Signer that is able to sign several messages in a single call.
+//
+// Signers backed by remote services can implement this interface to reduce the number
+// of round trips when many messages are signed with the same key.
+type BatchSigner interface {
+	Signer
+	// SignBatch signs each of the given messages with this signer.
+	//
+	// The returned signatures are in the same order as the messages.
+	SignBatch(ctx context.Context, messages [][]byte) ([][]byte, error)
+}
+
 // An InMemorySigner is a signer that generates signatures using an in-memory private key.
 //
 // InMemorySigner implements simple signing that does not protect the private key against
@@ -105,7 +181,7 @@ type InMemorySigner struct {
 	Hasher     Hasher
 }
 
-var _ Signer = (*InMemorySigner)(nil)
+var _ ContextSigner = (*InMemorySigner)(nil)
 
 // NewInMemorySigner initializes and returns a new in-memory signer with the provided private key
 // and hashing algorithm.
@@ -133,6 +209,16 @@ func (s InMemorySigner) Sign(message []byte) ([]byte, error) {
 	return s.PrivateKey.Sign(message, s.Hasher)
 }
 
+// SignContext signs the given message unless the context is already done.
+//
+// In-memory signing is not interruptible, the context is only checked before signing.
+func (s InMemorySigner) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.Sign(message)
+}
+
 func (s InMemorySigner) PublicKey() PublicKey {
 	return s.PrivateKey.PublicKey()
 }
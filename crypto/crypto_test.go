@@ -19,12 +19,14 @@
 package crypto_test
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
 	"encoding/pem"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -197,3 +199,66 @@ hbOhaN5CWXjw0HQAZeXqjoswiWlVH0baBuwAPwFcdk5fG/KW60QvOYPExA==
 		assert.Equal(t, expected[key], pk.String())
 	})
 }
+
+// blockingSigner is a signer that blocks until its channel is closed.
+type blockingSigner struct {
+	release chan struct{}
+}
+
+func (s blockingSigner) Sign(message []byte) ([]byte, error) {
+	<-s.release
+	return message, nil
+}
+
+func (s blockingSigner) PublicKey() crypto.PublicKey {
+	return nil
+}
+
+func TestNewContextSigner(t *testing.T) {
+	message := []byte("random_message")
+
+	t.Run("ContextSigner is returned as is", func(t *testing.T) {
+		sk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, makeSeed(t, crypto.MinSeedLength))
+		require.NoError(t, err)
+		signer, err := crypto.NewInMemorySigner(sk, crypto.SHA3_256)
+		require.NoError(t, err)
+
+		assert.Equal(t, signer, crypto.NewContextSigner(signer))
+	})
+
+	t.Run("Signer is adapted", func(t *testing.T) {
+		release := make(chan struct{})
+		close(release)
+		signer := crypto.NewContextSigner(blockingSigner{release})
+
+		sig, err := signer.SignContext(context.Background(), message)
+		require.NoError(t, err)
+		assert.Equal(t, message, sig)
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		release := make(chan struct{})
+		close(release)
+		signer := crypto.NewContextSigner(blockingSigner{release})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		sig, err := signer.SignContext(ctx, message)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, sig)
+	})
+
+	t.Run("Deadline exceeded while signing", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		signer := crypto.NewContextSigner(blockingSigner{release})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		sig, err := signer.SignContext(ctx, message)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, sig)
+	})
+}
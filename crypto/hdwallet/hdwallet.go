@@ -0,0 +1,199 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package hdwallet derives Flow account keys from BIP39 mnemonics.
+//
+// Keys are derived from the mnemonic seed along a BIP32 derivation path, following SLIP-10
+// for both supported curves: ECDSA_secp256k1 keys are derived exactly as in BIP32, and
+// ECDSA_P256 keys use the SLIP-10 NIST P-256 derivation. Flow wallets derive account keys
+// with the path `m/44'/539'/0'/0/0`, 539 being the SLIP-44 coin type of Flow.
+//
+// References:
+//   - https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki
+//   - https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+//   - https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+package hdwallet
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// FlowCoinType is the SLIP-44 coin type of Flow.
+const FlowCoinType = 539
+
+// HardenedOffset is the first hardened child index.
+const HardenedOffset uint32 = 1 << 31
+
+// DefaultPath is the derivation path of the first account key used by Flow wallets.
+const DefaultPath = "m/44'/539'/0'/0/0"
+
+var secp256k1Order, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+
+// curveParams are the SLIP-10 parameters of a curve.
+type curveParams struct {
+	// key of the HMAC computing the master key from the seed
+	seedKey []byte
+	// order of the curve
+	order *big.Int
+}
+
+func paramsForAlgorithm(sigAlgo crypto.SignatureAlgorithm) (curveParams, error) {
+	switch sigAlgo {
+	case crypto.ECDSA_secp256k1:
+		return curveParams{seedKey: []byte("Bitcoin seed"), order: secp256k1Order}, nil
+	case crypto.ECDSA_P256:
+		return curveParams{seedKey: []byte("Nist256p1 seed"), order: elliptic.P256().Params().N}, nil
+	default:
+		return curveParams{}, fmt.Errorf("hdwallet: unsupported signature algorithm %s", sigAlgo)
+	}
+}
+
+// ParsePath parses a BIP32 derivation path such as `m/44'/539'/0'/0/0` into child indexes.
+//
+// Hardened indexes are marked with `'` or `h`.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("hdwallet: invalid derivation path %q, must start with m", path)
+	}
+
+	indexes := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		offset := uint32(0)
+		if strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h") || strings.HasSuffix(segment, "H") {
+			offset = HardenedOffset
+			segment = segment[:len(segment)-1]
+		}
+
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("hdwallet: invalid derivation path %q, invalid index %q", path, segment)
+		}
+		indexes = append(indexes, uint32(index)+offset)
+	}
+
+	return indexes, nil
+}
+
+// DerivePrivateKey derives the private key at the given path from a BIP39 seed.
+//
+// Only ECDSA_P256 and ECDSA_secp256k1 are supported.
+func DerivePrivateKey(sigAlgo crypto.SignatureAlgorithm, seed []byte, path string) (crypto.PrivateKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	params, err := paramsForAlgorithm(sigAlgo)
+	if err != nil {
+		return nil, err
+	}
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("hdwallet: invalid seed length %d, must be between 16 and 64 bytes", len(seed))
+	}
+
+	key, chainCode := params.masterKey(seed)
+	for _, index := range indexes {
+		key, chainCode, err = params.childKey(sigAlgo, key, chainCode, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	privateKey, err := crypto.DecodePrivateKey(sigAlgo, key.FillBytes(make([]byte, 32)))
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: failed to decode derived key: %w", err)
+	}
+	return privateKey, nil
+}
+
+// PrivateKeyFromMnemonic derives the private key at the given path from a BIP39 mnemonic
+// and its optional passphrase.
+func PrivateKeyFromMnemonic(
+	sigAlgo crypto.SignatureAlgorithm,
+	mnemonic string,
+	passphrase string,
+	path string,
+) (crypto.PrivateKey, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return DerivePrivateKey(sigAlgo, seed, path)
+}
+
+// masterKey derives the master private key and chain code from the seed.
+func (p curveParams) masterKey(seed []byte) (*big.Int, []byte) {
+	data := seed
+	for {
+		i := hmacSHA512(p.seedKey, data)
+		key := new(big.Int).SetBytes(i[:32])
+		if key.Sign() != 0 && key.Cmp(p.order) < 0 {
+			return key, i[32:]
+		}
+		// the probability of an invalid key is negligible, SLIP-10 retries with the HMAC output
+		data = i
+	}
+}
+
+// childKey derives the private key and chain code of the child with the given index.
+func (p curveParams) childKey(
+	sigAlgo crypto.SignatureAlgorithm,
+	key *big.Int,
+	chainCode []byte,
+	index uint32,
+) (*big.Int, []byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= HardenedOffset {
+		data = append(data, 0)
+		data = append(data, key.FillBytes(make([]byte, 32))...)
+	} else {
+		parent, err := crypto.DecodePrivateKey(sigAlgo, key.FillBytes(make([]byte, 32)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("hdwallet: failed to decode parent key: %w", err)
+		}
+		data = append(data, parent.PublicKey().EncodeCompressed()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	for {
+		i := hmacSHA512(chainCode, data)
+		tweak := new(big.Int).SetBytes(i[:32])
+		child := new(big.Int).Add(tweak, key)
+		child.Mod(child, p.order)
+		if tweak.Cmp(p.order) < 0 && child.Sign() != 0 {
+			return child, i[32:], nil
+		}
+		// the probability of an invalid key is negligible, SLIP-10 retries with the HMAC output
+		data = binary.BigEndian.AppendUint32(append([]byte{1}, i[32:]...), index)
+	}
+}
+
+func hmacSHA512(key []byte, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
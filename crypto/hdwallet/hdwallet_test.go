@@ -0,0 +1,174 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hdwallet_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/hdwallet"
+)
+
+// test vectors from https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+func TestMnemonic(t *testing.T) {
+	vectors := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		},
+	}
+
+	for _, v := range vectors {
+		entropy, err := hex.DecodeString(v.entropy)
+		require.NoError(t, err)
+
+		mnemonic, err := hdwallet.MnemonicFromEntropy(entropy)
+		require.NoError(t, err)
+		assert.Equal(t, v.mnemonic, mnemonic)
+		assert.NoError(t, hdwallet.ValidateMnemonic(mnemonic))
+
+		if v.seed != "" {
+			seed, err := hdwallet.SeedFromMnemonic(mnemonic, "TREZOR")
+			require.NoError(t, err)
+			assert.Equal(t, v.seed, hex.EncodeToString(seed))
+		}
+	}
+
+	t.Run("Random mnemonic", func(t *testing.T) {
+		for _, bits := range []int{128, 160, 192, 224, 256} {
+			mnemonic, err := hdwallet.NewMnemonic(bits)
+			require.NoError(t, err)
+			assert.Len(t, strings.Fields(mnemonic), bits*3/32)
+			assert.NoError(t, hdwallet.ValidateMnemonic(mnemonic))
+		}
+
+		_, err := hdwallet.NewMnemonic(100)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid mnemonic", func(t *testing.T) {
+		for _, mnemonic := range []string{
+			"",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon flow",
+		} {
+			assert.ErrorIs(t, hdwallet.ValidateMnemonic(mnemonic), hdwallet.ErrInvalidMnemonic, mnemonic)
+		}
+	})
+}
+
+func TestParsePath(t *testing.T) {
+	indexes, err := hdwallet.ParsePath(hdwallet.DefaultPath)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{
+		44 + hdwallet.HardenedOffset,
+		hdwallet.FlowCoinType + hdwallet.HardenedOffset,
+		hdwallet.HardenedOffset,
+		0,
+		0,
+	}, indexes)
+
+	for _, path := range []string{"", "44'/539'", "m/a", "m/2147483648", "m//0"} {
+		_, err := hdwallet.ParsePath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+// test vectors from https://github.com/satoshilabs/slips/blob/master/slip-0010.md (test vector 1)
+func TestDerivePrivateKey(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	vectors := map[crypto.SignatureAlgorithm]map[string]string{
+		crypto.ECDSA_secp256k1: {
+			"m":         "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			"m/0H":      "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			"m/0H/1":    "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			"m/0H/1/2H": "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+			"m/0'/1/2'": "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+		},
+		crypto.ECDSA_P256: {
+			"m":         "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2",
+			"m/0H":      "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c",
+			"m/0H/1":    "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129",
+			"m/0H/1/2H": "694596e8a54f252c960eb771a3c41e7e32496d03b954aeb90f61635b8e092aa7",
+		},
+	}
+
+	for sigAlgo, keys := range vectors {
+		t.Run(sigAlgo.String(), func(t *testing.T) {
+			for path, expected := range keys {
+				sk, err := hdwallet.DerivePrivateKey(sigAlgo, seed, path)
+				require.NoError(t, err, path)
+				assert.Equal(t, sigAlgo, sk.Algorithm())
+				assert.Equal(t, expected, hex.EncodeToString(sk.Encode()), path)
+			}
+		})
+	}
+
+	t.Run("Unsupported algorithm", func(t *testing.T) {
+		_, err := hdwallet.DerivePrivateKey(crypto.BLS_BLS12_381, seed, hdwallet.DefaultPath)
+		assert.Error(t, err)
+	})
+}
+
+func TestPrivateKeyFromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	for _, sigAlgo := range []crypto.SignatureAlgorithm{crypto.ECDSA_P256, crypto.ECDSA_secp256k1} {
+		sk, err := hdwallet.PrivateKeyFromMnemonic(sigAlgo, mnemonic, "", hdwallet.DefaultPath)
+		require.NoError(t, err)
+
+		// derivation is deterministic, and depends on the passphrase and the path
+		same, err := hdwallet.PrivateKeyFromMnemonic(sigAlgo, mnemonic, "", hdwallet.DefaultPath)
+		require.NoError(t, err)
+		assert.True(t, sk.Equals(same))
+
+		withPassphrase, err := hdwallet.PrivateKeyFromMnemonic(sigAlgo, mnemonic, "passphrase", hdwallet.DefaultPath)
+		require.NoError(t, err)
+		assert.False(t, sk.Equals(withPassphrase))
+
+		nextKey, err := hdwallet.PrivateKeyFromMnemonic(sigAlgo, mnemonic, "", "m/44'/539'/0'/0/1")
+		require.NoError(t, err)
+		assert.False(t, sk.Equals(nextKey))
+	}
+
+	_, err := hdwallet.PrivateKeyFromMnemonic(crypto.ECDSA_P256, "abandon abandon", "", hdwallet.DefaultPath)
+	assert.ErrorIs(t, err, hdwallet.ErrInvalidMnemonic)
+}
@@ -0,0 +1,161 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hdwallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SeedLength is the length in bytes of the seed derived from a mnemonic.
+const SeedLength = 64
+
+// ErrInvalidMnemonic is returned when a mnemonic is not a valid BIP39 English mnemonic.
+var ErrInvalidMnemonic = errors.New("hdwallet: invalid mnemonic")
+
+// english is the BIP39 English wordlist.
+//
+// Reference: https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+//
+//go:embed english.txt
+var english string
+
+var (
+	wordList  = strings.Fields(english)
+	wordIndex = func() map[string]int {
+		index := make(map[string]int, len(wordList))
+		for i, word := range wordList {
+			index[word] = i
+		}
+		return index
+	}()
+)
+
+// NewMnemonic generates a random BIP39 English mnemonic with the given entropy size in bits.
+//
+// The entropy size must be a multiple of 32 between 128 and 256, and results in a
+// mnemonic of 12 to 24 words.
+func NewMnemonic(bits int) (string, error) {
+	if err := validateEntropySize(bits); err != nil {
+		return "", err
+	}
+
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("hdwallet: failed to generate entropy: %w", err)
+	}
+
+	return MnemonicFromEntropy(entropy)
+}
+
+// MnemonicFromEntropy returns the BIP39 English mnemonic encoding the given entropy.
+//
+// The entropy must be 16 to 32 bytes long, in multiples of 4 bytes.
+func MnemonicFromEntropy(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if err := validateEntropySize(bits); err != nil {
+		return "", err
+	}
+
+	// the checksum is the first bits/32 bits of the SHA-256 hash of the entropy
+	checksumBits := bits / 32
+	hash := sha256.Sum256(entropy)
+
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(checksumBits))
+	data.Or(data, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	// each word encodes 11 bits, starting from the most significant bits
+	wordCount := (bits + checksumBits) / 11
+	words := make([]string, wordCount)
+	mask := big.NewInt(2047)
+	for i := wordCount - 1; i >= 0; i-- {
+		index := new(big.Int).And(data, mask)
+		words[i] = wordList[index.Int64()]
+		data.Rsh(data, 11)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// ValidateMnemonic checks the mnemonic only contains words of the BIP39 English wordlist,
+// has a valid length and a valid checksum.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := entropyFromMnemonic(mnemonic)
+	return err
+}
+
+// SeedFromMnemonic validates the mnemonic and returns the 64-byte BIP39 seed
+// derived from the mnemonic and the optional passphrase.
+func SeedFromMnemonic(mnemonic string, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	normalized := norm.NFKD.String(strings.Join(strings.Fields(mnemonic), " "))
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+
+	return pbkdf2.Key([]byte(normalized), []byte(salt), 2048, SeedLength, sha512.New), nil
+}
+
+// entropyFromMnemonic decodes the entropy encoded by a mnemonic and verifies its checksum.
+func entropyFromMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, fmt.Errorf("%w: unsupported number of words %d", ErrInvalidMnemonic, len(words))
+	}
+
+	data := new(big.Int)
+	for _, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+
+	checksumBits := len(words) * 11 / 33
+	checksum := new(big.Int).And(data, big.NewInt(1<<checksumBits-1))
+	data.Rsh(data, uint(checksumBits))
+
+	entropy := data.FillBytes(make([]byte, checksumBits*4))
+	hash := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(hash[0]>>(8-checksumBits)) {
+		return nil, fmt.Errorf("%w: invalid checksum", ErrInvalidMnemonic)
+	}
+
+	return entropy, nil
+}
+
+func validateEntropySize(bits int) error {
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return fmt.Errorf("hdwallet: invalid entropy size %d bits, must be a multiple of 32 between 128 and 256", bits)
+	}
+	return nil
+}
@@ -44,6 +44,17 @@ func ParseECDSASignature(kmsSignature []byte, curve crypto.SignatureAlgorithm) (
 	return signature, nil
 }
 
+// NormalizeECDSASignature returns an ECDSA signature in the raw R||S form required by the `Signer.Sign` method.
+//
+// Signatures that already have the raw length are returned as is, other signatures
+// are parsed as ASN.1 structures.
+func NormalizeECDSASignature(signature []byte, curve crypto.SignatureAlgorithm) ([]byte, error) {
+	if len(signature) == 2*curveOrder(curve) {
+		return signature, nil
+	}
+	return ParseECDSASignature(signature, curve)
+}
+
 // returns the curve order size in bytes (used to pad R and S of the ECDSA signature)
 // Only P-256 and secp256k1 are supported. The calling function should make sure
 // the function is only called with one of the 2 curves.
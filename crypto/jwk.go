@@ -0,0 +1,184 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypto
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// JWK parameters of the supported keys (RFC 7518 and RFC 8812).
+const (
+	jwkKeyTypeEC       = "EC"
+	jwkCurveP256       = "P-256"
+	jwkCurveSecp256k1  = "secp256k1"
+	jwkCoordinateBytes = 32
+)
+
+// JWK is an elliptic curve JSON Web Key (RFC 7517).
+//
+// Only ECDSA keys on P-256 and secp256k1 curves are supported. A JWK holds a private key if `D` is set.
+type JWK struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+	D       string `json:"d,omitempty"`
+	KeyID   string `json:"kid,omitempty"`
+	Use     string `json:"use,omitempty"`
+	// Algorithm is the JWS algorithm the key is used with, for instance "ES256" or "ES256K".
+	Algorithm string `json:"alg,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// EncodePublicKeyJWK returns the JWK of an ECDSA public key.
+//
+// The function only supports ECDSA with P256 and secp256k1 curves.
+func EncodePublicKeyJWK(pk PublicKey) (JWK, error) {
+	curve, err := jwkCurve(pk.Algorithm())
+	if err != nil {
+		return JWK{}, err
+	}
+
+	point := pk.Encode()
+	return JWK{
+		KeyType: jwkKeyTypeEC,
+		Curve:   curve,
+		X:       base64.RawURLEncoding.EncodeToString(point[:jwkCoordinateBytes]),
+		Y:       base64.RawURLEncoding.EncodeToString(point[jwkCoordinateBytes:]),
+	}, nil
+}
+
+// EncodePrivateKeyJWK returns the JWK of an ECDSA private key, including its public part.
+//
+// The function only supports ECDSA with P256 and secp256k1 curves.
+func EncodePrivateKeyJWK(sk PrivateKey) (JWK, error) {
+	jwk, err := EncodePublicKeyJWK(sk.PublicKey())
+	if err != nil {
+		return JWK{}, err
+	}
+
+	jwk.D = base64.RawURLEncoding.EncodeToString(sk.Encode())
+	return jwk, nil
+}
+
+// SignatureAlgorithm returns the signature algorithm of the key, based on its curve.
+func (k JWK) SignatureAlgorithm() SignatureAlgorithm {
+	if k.KeyType != jwkKeyTypeEC {
+		return UnknownSignatureAlgorithm
+	}
+
+	switch k.Curve {
+	case jwkCurveP256:
+		return ECDSA_P256
+	// "P-256K" is used by some cloud providers, in particular Azure Key Vault
+	case jwkCurveSecp256k1, "P-256K":
+		return ECDSA_secp256k1
+	default:
+		return UnknownSignatureAlgorithm
+	}
+}
+
+// PublicKey decodes the public key of the JWK.
+func (k JWK) PublicKey() (PublicKey, error) {
+	sigAlgo := k.SignatureAlgorithm()
+	if sigAlgo == UnknownSignatureAlgorithm {
+		return nil, fmt.Errorf("crypto: unsupported JWK key type %q and curve %q", k.KeyType, k.Curve)
+	}
+
+	x, err := decodeJWKParameter(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: invalid JWK x coordinate: %w", err)
+	}
+	y, err := decodeJWKParameter(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: invalid JWK y coordinate: %w", err)
+	}
+
+	// decode the point and check the resulting key is a valid point on the curve
+	return DecodePublicKey(sigAlgo, append(x, y...))
+}
+
+// PrivateKey decodes the private key of the JWK.
+//
+// It returns an error if the JWK doesn't hold a private key, or if its public part
+// doesn't match the private key.
+func (k JWK) PrivateKey() (PrivateKey, error) {
+	if k.D == "" {
+		return nil, fmt.Errorf("crypto: JWK doesn't hold a private key")
+	}
+
+	pk, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	d, err := decodeJWKParameter(k.D)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: invalid JWK private key: %w", err)
+	}
+
+	sk, err := DecodePrivateKey(pk.Algorithm(), d)
+	if err != nil {
+		return nil, err
+	}
+	if !sk.PublicKey().Equals(pk) {
+		return nil, fmt.Errorf("crypto: JWK public key doesn't match the private key")
+	}
+	return sk, nil
+}
+
+// Find returns the key of the set with the given key ID.
+func (s JWKS) Find(keyID string) (JWK, bool) {
+	for _, key := range s.Keys {
+		if key.KeyID == keyID {
+			return key, true
+		}
+	}
+	return JWK{}, false
+}
+
+func jwkCurve(sigAlgo SignatureAlgorithm) (string, error) {
+	switch sigAlgo {
+	case ECDSA_P256:
+		return jwkCurveP256, nil
+	case ECDSA_secp256k1:
+		return jwkCurveSecp256k1, nil
+	default:
+		return "", fmt.Errorf("crypto: only ECDSA algorithms are supported")
+	}
+}
+
+// decodeJWKParameter decodes a base64url coordinate or scalar, left-padded to the field size.
+func decodeJWKParameter(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > jwkCoordinateBytes {
+		return nil, fmt.Errorf("invalid length %d", len(b))
+	}
+
+	padded := make([]byte, jwkCoordinateBytes)
+	copy(padded[jwkCoordinateBytes-len(b):], b)
+	return padded, nil
+}
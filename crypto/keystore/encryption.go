@@ -0,0 +1,279 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// Key derivation functions supported by the keystore.
+const (
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+)
+
+const (
+	cipherAES256GCM = "aes-256-gcm"
+	derivedKeyLen   = 32
+	saltLen         = 32
+)
+
+// encryptedData is an encrypted private key with the parameters needed to decrypt it.
+type encryptedData struct {
+	KDF        string          `json:"kdf"`
+	Scrypt     *scryptParams   `json:"scrypt,omitempty"`
+	Argon2id   *argon2idParams `json:"argon2id,omitempty"`
+	Salt       string          `json:"salt"`
+	Cipher     string          `json:"cipher"`
+	Nonce      string          `json:"nonce"`
+	Ciphertext string          `json:"ciphertext"`
+}
+
+type scryptParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+type argon2idParams struct {
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// kdfConfig is the key derivation function used to encrypt new keys.
+type kdfConfig struct {
+	name     string
+	scrypt   scryptParams
+	argon2id argon2idParams
+}
+
+func defaultKDFConfig() kdfConfig {
+	return kdfConfig{
+		name:     KDFScrypt,
+		scrypt:   scryptParams{N: 1 << 17, R: 8, P: 1},
+		argon2id: argon2idParams{Time: 3, Memory: 64 * 1024, Threads: 4},
+	}
+}
+
+// maxKDFMemory is the maximum memory in bytes a key derivation may use, so that
+// a crafted keystore file can't exhaust the memory of the process opening it.
+const maxKDFMemory = 1 << 30
+
+const (
+	maxScryptP    = 16
+	maxArgon2Time = 16
+)
+
+func (c kdfConfig) validate() error {
+	switch c.name {
+	case KDFScrypt:
+		return c.scrypt.validate()
+	case KDFArgon2id:
+		return c.argon2id.validate()
+	default:
+		return fmt.Errorf("keystore: unsupported key derivation function %s", c.name)
+	}
+}
+
+func (p scryptParams) validate() error {
+	if p.N <= 1 || p.N&(p.N-1) != 0 || p.R <= 0 || p.P <= 0 || p.P > maxScryptP {
+		return errors.New("keystore: invalid scrypt parameters")
+	}
+	// scrypt uses 128*N*r bytes of memory
+	if uint64(p.N) > maxKDFMemory/128/uint64(p.R) {
+		return errors.New("keystore: invalid scrypt parameters: memory cost too high")
+	}
+	return nil
+}
+
+func (p argon2idParams) validate() error {
+	if p.Time == 0 || p.Time > maxArgon2Time || p.Memory == 0 || p.Threads == 0 {
+		return errors.New("keystore: invalid argon2id parameters")
+	}
+	// argon2id memory is in KiB
+	if uint64(p.Memory) > maxKDFMemory/1024 {
+		return errors.New("keystore: invalid argon2id parameters: memory cost too high")
+	}
+	return nil
+}
+
+// deriveKey derives the encryption key from the passphrase, using the parameters of the encrypted data.
+//
+// The parameters are read from the keystore file, so they are validated before running the
+// key derivation function.
+func (d encryptedData) deriveKey(passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(d.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: invalid salt: %w", err)
+	}
+
+	switch {
+	case d.KDF == KDFScrypt && d.Scrypt != nil:
+		if err := d.Scrypt.validate(); err != nil {
+			return nil, err
+		}
+		key, err := scrypt.Key([]byte(passphrase), salt, d.Scrypt.N, d.Scrypt.R, d.Scrypt.P, derivedKeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("keystore: failed to derive key: %w", err)
+		}
+		return key, nil
+	case d.KDF == KDFArgon2id && d.Argon2id != nil:
+		if err := d.Argon2id.validate(); err != nil {
+			return nil, err
+		}
+		return argon2.IDKey([]byte(passphrase), salt, d.Argon2id.Time, d.Argon2id.Memory, d.Argon2id.Threads, derivedKeyLen), nil
+	default:
+		return nil, fmt.Errorf("keystore: unsupported key derivation function %s", d.KDF)
+	}
+}
+
+// additionalData returns the metadata of the key authenticated along with the encrypted private key,
+// so that the metadata of the stored key can't be tampered with.
+func (k storedKey) additionalData() []byte {
+	return []byte(k.Name + "/" + k.SignatureAlgorithm + "/" + k.HashAlgorithm + "/" + k.PublicKey)
+}
+
+// encryptKey encrypts a private key with a key derived from the passphrase.
+func encryptKey(
+	name string,
+	privateKey crypto.PrivateKey,
+	hashAlgo crypto.HashAlgorithm,
+	passphrase string,
+	kdf kdfConfig,
+) (storedKey, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return storedKey{}, fmt.Errorf("keystore: failed to generate salt: %w", err)
+	}
+
+	key := storedKey{
+		Name:               name,
+		SignatureAlgorithm: privateKey.Algorithm().String(),
+		HashAlgorithm:      hashAlgo.String(),
+		PublicKey:          hex.EncodeToString(privateKey.PublicKey().Encode()),
+		Crypto: encryptedData{
+			KDF:    kdf.name,
+			Salt:   hex.EncodeToString(salt),
+			Cipher: cipherAES256GCM,
+		},
+	}
+	if kdf.name == KDFScrypt {
+		params := kdf.scrypt
+		key.Crypto.Scrypt = &params
+	} else {
+		params := kdf.argon2id
+		key.Crypto.Argon2id = &params
+	}
+
+	derivedKey, err := key.Crypto.deriveKey(passphrase)
+	if err != nil {
+		return storedKey{}, err
+	}
+	aead, err := newAEAD(derivedKey)
+	if err != nil {
+		return storedKey{}, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return storedKey{}, fmt.Errorf("keystore: failed to generate nonce: %w", err)
+	}
+
+	ciphertext := aead.Seal(nil, nonce, privateKey.Encode(), key.additionalData())
+	key.Crypto.Nonce = hex.EncodeToString(nonce)
+	key.Crypto.Ciphertext = hex.EncodeToString(ciphertext)
+
+	return key, nil
+}
+
+// decrypt decrypts the private key with a key derived from the passphrase.
+func (k storedKey) decrypt(passphrase string) (crypto.PrivateKey, error) {
+	if k.Crypto.Cipher != cipherAES256GCM {
+		return nil, fmt.Errorf("keystore: unsupported cipher %s", k.Crypto.Cipher)
+	}
+
+	derivedKey, err := k.Crypto.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := hex.DecodeString(k.Crypto.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, errors.New("keystore: invalid nonce")
+	}
+	ciphertext, err := hex.DecodeString(k.Crypto.Ciphertext)
+	if err != nil {
+		return nil, errors.New("keystore: invalid ciphertext")
+	}
+
+	encodedKey, err := aead.Open(nil, nonce, ciphertext, k.additionalData())
+	if err != nil {
+		// the authentication also fails if the file was tampered with
+		return nil, ErrWrongPassphrase
+	}
+
+	privateKey, err := crypto.DecodePrivateKey(crypto.StringToSignatureAlgorithm(k.SignatureAlgorithm), encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: failed to decode private key: %w", err)
+	}
+	return privateKey, nil
+}
+
+// info returns the metadata of the stored key.
+func (k storedKey) info() (KeyInfo, error) {
+	sigAlgo := crypto.StringToSignatureAlgorithm(k.SignatureAlgorithm)
+	publicKey, err := crypto.DecodePublicKeyHex(sigAlgo, k.PublicKey)
+	if err != nil {
+		return KeyInfo{}, fmt.Errorf("keystore: failed to decode public key of key %s: %w", k.Name, err)
+	}
+
+	return KeyInfo{
+		Name:               k.Name,
+		SignatureAlgorithm: sigAlgo,
+		HashAlgorithm:      crypto.StringToHashAlgorithm(k.HashAlgorithm),
+		PublicKey:          publicKey,
+	}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: failed to initialize cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: failed to initialize cipher: %w", err)
+	}
+	return aead, nil
+}
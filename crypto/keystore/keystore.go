@@ -0,0 +1,351 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package keystore provides an encrypted on-disk store of private keys.
+//
+// Private keys are encrypted at rest with AES-256-GCM, using a key derived from a passphrase
+// with scrypt or argon2id. The store is a versioned JSON file that also records the
+// signature and hash algorithms of each key, so that a ready-to-use signer can be
+// loaded from the store.
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// Version is the version of the keystore file format written by this package.
+const Version = 1
+
+// ErrKeyNotFound is returned when no key with the requested name is in the store.
+var ErrKeyNotFound = errors.New("keystore: key not found")
+
+// ErrKeyExists is returned when importing a key with a name already in the store.
+var ErrKeyExists = errors.New("keystore: key already exists")
+
+// ErrWrongPassphrase is returned when a key can't be decrypted with the given passphrase.
+var ErrWrongPassphrase = errors.New("keystore: wrong passphrase")
+
+// fileFormat is the JSON content of a keystore file.
+type fileFormat struct {
+	Version int         `json:"version"`
+	Keys    []storedKey `json:"keys"`
+}
+
+// storedKey is an encrypted private key with its metadata.
+type storedKey struct {
+	Name               string        `json:"name"`
+	SignatureAlgorithm string        `json:"signatureAlgorithm"`
+	HashAlgorithm      string        `json:"hashAlgorithm"`
+	PublicKey          string        `json:"publicKey"`
+	Crypto             encryptedData `json:"crypto"`
+}
+
+// KeyInfo describes a key of the store, without its private part.
+type KeyInfo struct {
+	Name               string
+	SignatureAlgorithm crypto.SignatureAlgorithm
+	HashAlgorithm      crypto.HashAlgorithm
+	PublicKey          crypto.PublicKey
+}
+
+// Keystore is an encrypted on-disk store of private keys.
+//
+// All the keys of a store are encrypted with the same passphrase. The passphrase is never kept
+// in memory by the store, it must be given to each operation requiring it.
+//
+// A Keystore is safe for concurrent use, but a file should not be opened by several Keystores at once.
+type Keystore struct {
+	mu   sync.Mutex
+	path string
+	kdf  kdfConfig
+	file fileFormat
+}
+
+// Option is a configuration option for the keystore.
+type Option func(*kdfConfig)
+
+// WithScrypt sets scrypt as the key derivation function for newly encrypted keys,
+// with the given cost parameters. The parameters can't use more than 1 GiB of memory.
+func WithScrypt(n, r, p int) Option {
+	return func(config *kdfConfig) {
+		config.name = KDFScrypt
+		config.scrypt = scryptParams{N: n, R: r, P: p}
+	}
+}
+
+// WithArgon2id sets argon2id as the key derivation function for newly encrypted keys,
+// with the given cost parameters. The memory is in KiB, and can't exceed 1 GiB.
+func WithArgon2id(time, memory uint32, threads uint8) Option {
+	return func(config *kdfConfig) {
+		config.name = KDFArgon2id
+		config.argon2id = argon2idParams{Time: time, Memory: memory, Threads: threads}
+	}
+}
+
+// Create creates a new empty keystore file at the given path.
+//
+// It returns an error if the file already exists.
+// Keys are encrypted with scrypt by default.
+func Create(path string, opts ...Option) (*Keystore, error) {
+	ks := &Keystore{
+		path: path,
+		kdf:  defaultKDFConfig(),
+		file: fileFormat{
+			Version: Version,
+			Keys:    []storedKey{},
+		},
+	}
+	for _, apply := range opts {
+		apply(&ks.kdf)
+	}
+	if err := ks.kdf.validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("keystore: file %s already exists", path)
+	}
+
+	if err := ks.save(ks.file.Keys); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Open opens an existing keystore file.
+//
+// Options only apply to keys encrypted after the store is opened.
+func Open(path string, opts ...Option) (*Keystore, error) {
+	ks := &Keystore{
+		path: path,
+		kdf:  defaultKDFConfig(),
+	}
+	for _, apply := range opts {
+		apply(&ks.kdf)
+	}
+	if err := ks.kdf.validate(); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: failed to read file: %w", err)
+	}
+	if err := json.Unmarshal(content, &ks.file); err != nil {
+		return nil, fmt.Errorf("keystore: failed to decode file: %w", err)
+	}
+	if ks.file.Version != Version {
+		return nil, fmt.Errorf("keystore: unsupported file version %d", ks.file.Version)
+	}
+
+	return ks, nil
+}
+
+// List returns the keys of the store, sorted by name.
+func (ks *Keystore) List() ([]KeyInfo, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	infos := make([]KeyInfo, 0, len(ks.file.Keys))
+	for _, key := range ks.file.Keys {
+		info, err := key.info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos, nil
+}
+
+// Import encrypts the private key with the passphrase and adds it to the store under the given name.
+//
+// The hash algorithm is the algorithm used with the key when signing. If the store already
+// holds keys, the passphrase must be the passphrase of the store.
+func (ks *Keystore) Import(name string, privateKey crypto.PrivateKey, hashAlgo crypto.HashAlgorithm, passphrase string) error {
+	if name == "" {
+		return errors.New("keystore: missing key name")
+	}
+	if !crypto.CompatibleAlgorithms(privateKey.Algorithm(), hashAlgo) {
+		return fmt.Errorf("keystore: signature algorithm %s and hashing algorithm %s are incompatible",
+			privateKey.Algorithm(), hashAlgo)
+	}
+	// keys are loaded as in-memory signers, which require a hasher instantiable with NewHasher
+	if _, err := crypto.NewHasher(hashAlgo); err != nil {
+		return fmt.Errorf("keystore: unsupported hashing algorithm %s: %w", hashAlgo, err)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if ks.find(name) >= 0 {
+		return ErrKeyExists
+	}
+
+	// all the keys of a store share the same passphrase
+	if len(ks.file.Keys) > 0 {
+		if _, err := ks.file.Keys[0].decrypt(passphrase); err != nil {
+			return err
+		}
+	}
+
+	key, err := encryptKey(name, privateKey, hashAlgo, passphrase, ks.kdf)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]storedKey, 0, len(ks.file.Keys)+1)
+	keys = append(append(keys, ks.file.Keys...), key)
+	if err := ks.save(keys); err != nil {
+		return err
+	}
+	ks.file.Keys = keys
+	return nil
+}
+
+// Export decrypts and returns the private key with the given name, with its hash algorithm.
+func (ks *Keystore) Export(name string, passphrase string) (crypto.PrivateKey, crypto.HashAlgorithm, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	i := ks.find(name)
+	if i < 0 {
+		return nil, crypto.UnknownHashAlgorithm, ErrKeyNotFound
+	}
+
+	key := ks.file.Keys[i]
+	privateKey, err := key.decrypt(passphrase)
+	if err != nil {
+		return nil, crypto.UnknownHashAlgorithm, err
+	}
+
+	return privateKey, crypto.StringToHashAlgorithm(key.HashAlgorithm), nil
+}
+
+// Signer decrypts the private key with the given name and returns an in-memory signer using
+// the key and its hash algorithm.
+func (ks *Keystore) Signer(name string, passphrase string) (crypto.InMemorySigner, error) {
+	privateKey, hashAlgo, err := ks.Export(name, passphrase)
+	if err != nil {
+		return crypto.InMemorySigner{}, err
+	}
+
+	return crypto.NewInMemorySigner(privateKey, hashAlgo)
+}
+
+// Delete removes the key with the given name from the store.
+func (ks *Keystore) Delete(name string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	i := ks.find(name)
+	if i < 0 {
+		return ErrKeyNotFound
+	}
+
+	keys := make([]storedKey, 0, len(ks.file.Keys)-1)
+	keys = append(append(keys, ks.file.Keys[:i]...), ks.file.Keys[i+1:]...)
+	if err := ks.save(keys); err != nil {
+		return err
+	}
+	ks.file.Keys = keys
+	return nil
+}
+
+// ChangePassphrase re-encrypts all the keys of the store with a new passphrase.
+//
+// The store is left unchanged if any key can't be decrypted with the old passphrase.
+func (ks *Keystore) ChangePassphrase(oldPassphrase string, newPassphrase string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	keys := make([]storedKey, len(ks.file.Keys))
+	for i, key := range ks.file.Keys {
+		privateKey, err := key.decrypt(oldPassphrase)
+		if err != nil {
+			return fmt.Errorf("keystore: failed to decrypt key %s: %w", key.Name, err)
+		}
+
+		keys[i], err = encryptKey(key.Name, privateKey, crypto.StringToHashAlgorithm(key.HashAlgorithm), newPassphrase, ks.kdf)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := ks.save(keys); err != nil {
+		return err
+	}
+	ks.file.Keys = keys
+	return nil
+}
+
+// find returns the index of the key with the given name, or -1 if the store doesn't hold the key.
+func (ks *Keystore) find(name string) int {
+	for i, key := range ks.file.Keys {
+		if key.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// save atomically writes the store with the given keys to its file, readable by the owner only.
+//
+// The in-memory store is not modified: callers update it once the file has been written,
+// so that the store and its file don't diverge when writing fails.
+func (ks *Keystore) save(keys []storedKey) error {
+	file := ks.file
+	file.Keys = keys
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("keystore: failed to encode file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(ks.path), filepath.Base(ks.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("keystore: failed to write file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("keystore: failed to write file: %w", err)
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("keystore: failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("keystore: failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), ks.path); err != nil {
+		return fmt.Errorf("keystore: failed to write file: %w", err)
+	}
+	return nil
+}
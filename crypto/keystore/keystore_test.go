@@ -0,0 +1,240 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package keystore_test
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/keystore"
+)
+
+// light key derivation parameters, to keep tests fast
+var kdfOptions = map[string]keystore.Option{
+	keystore.KDFScrypt:   keystore.WithScrypt(1<<10, 8, 1),
+	keystore.KDFArgon2id: keystore.WithArgon2id(1, 1024, 1),
+}
+
+func generateKey(t *testing.T, sigAlgo crypto.SignatureAlgorithm) crypto.PrivateKey {
+	seed := make([]byte, crypto.MinSeedLength)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	sk, err := crypto.GeneratePrivateKey(sigAlgo, seed)
+	require.NoError(t, err)
+	return sk
+}
+
+func TestKeystore(t *testing.T) {
+	for kdf, option := range kdfOptions {
+		t.Run(kdf, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.json")
+
+			ks, err := keystore.Create(path, option)
+			require.NoError(t, err)
+
+			skP256 := generateKey(t, crypto.ECDSA_P256)
+			skSecp256k1 := generateKey(t, crypto.ECDSA_secp256k1)
+
+			require.NoError(t, ks.Import("service", skP256, crypto.SHA3_256, "passphrase"))
+			require.NoError(t, ks.Import("alice", skSecp256k1, crypto.SHA2_256, "passphrase"))
+
+			t.Run("File content", func(t *testing.T) {
+				info, err := os.Stat(path)
+				require.NoError(t, err)
+				assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+				content, err := os.ReadFile(path)
+				require.NoError(t, err)
+				assert.NotContains(t, string(content), strings.TrimPrefix(skP256.String(), "0x"))
+
+				var file struct {
+					Version int `json:"version"`
+				}
+				require.NoError(t, json.Unmarshal(content, &file))
+				assert.Equal(t, keystore.Version, file.Version)
+			})
+
+			t.Run("List", func(t *testing.T) {
+				infos, err := ks.List()
+				require.NoError(t, err)
+				require.Len(t, infos, 2)
+
+				assert.Equal(t, "alice", infos[0].Name)
+				assert.Equal(t, crypto.ECDSA_secp256k1, infos[0].SignatureAlgorithm)
+				assert.Equal(t, crypto.SHA2_256, infos[0].HashAlgorithm)
+				assert.True(t, skSecp256k1.PublicKey().Equals(infos[0].PublicKey))
+
+				assert.Equal(t, "service", infos[1].Name)
+			})
+
+			t.Run("Import errors", func(t *testing.T) {
+				assert.ErrorIs(t, ks.Import("service", skP256, crypto.SHA3_256, "passphrase"), keystore.ErrKeyExists)
+				assert.ErrorIs(t, ks.Import("bob", skP256, crypto.SHA3_256, "other passphrase"), keystore.ErrWrongPassphrase)
+				assert.Error(t, ks.Import("bob", skP256, crypto.KMAC128, "passphrase"))
+			})
+
+			t.Run("Export from reopened store", func(t *testing.T) {
+				reopened, err := keystore.Open(path)
+				require.NoError(t, err)
+
+				sk, hashAlgo, err := reopened.Export("service", "passphrase")
+				require.NoError(t, err)
+				assert.True(t, skP256.Equals(sk))
+				assert.Equal(t, crypto.SHA3_256, hashAlgo)
+
+				_, _, err = reopened.Export("service", "wrong")
+				assert.ErrorIs(t, err, keystore.ErrWrongPassphrase)
+
+				_, _, err = reopened.Export("unknown", "passphrase")
+				assert.ErrorIs(t, err, keystore.ErrKeyNotFound)
+			})
+
+			t.Run("Signer", func(t *testing.T) {
+				signer, err := ks.Signer("alice", "passphrase")
+				require.NoError(t, err)
+
+				msg := []byte("random_message")
+				sig, err := signer.Sign(msg)
+				require.NoError(t, err)
+
+				valid, err := skSecp256k1.PublicKey().Verify(sig, msg, crypto.NewSHA2_256())
+				require.NoError(t, err)
+				assert.True(t, valid)
+			})
+
+			t.Run("Change passphrase", func(t *testing.T) {
+				assert.ErrorIs(t, ks.ChangePassphrase("wrong", "new passphrase"), keystore.ErrWrongPassphrase)
+
+				require.NoError(t, ks.ChangePassphrase("passphrase", "new passphrase"))
+
+				_, _, err := ks.Export("service", "passphrase")
+				assert.ErrorIs(t, err, keystore.ErrWrongPassphrase)
+
+				sk, _, err := ks.Export("service", "new passphrase")
+				require.NoError(t, err)
+				assert.True(t, skP256.Equals(sk))
+			})
+
+			t.Run("Delete", func(t *testing.T) {
+				require.NoError(t, ks.Delete("alice"))
+				assert.ErrorIs(t, ks.Delete("alice"), keystore.ErrKeyNotFound)
+
+				infos, err := ks.List()
+				require.NoError(t, err)
+				require.Len(t, infos, 1)
+				assert.Equal(t, "service", infos[0].Name)
+			})
+		})
+	}
+}
+
+func TestTamperedKeystore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	ks, err := keystore.Create(path, kdfOptions[keystore.KDFScrypt])
+	require.NoError(t, err)
+	require.NoError(t, ks.Import("service", generateKey(t, crypto.ECDSA_P256), crypto.SHA3_256, "passphrase"))
+
+	// changing the metadata of a key invalidates its encryption
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	tampered := strings.Replace(string(content), crypto.SHA3_256.String(), crypto.SHA2_256.String(), 1)
+	require.NoError(t, os.WriteFile(path, []byte(tampered), 0600))
+
+	ks, err = keystore.Open(path)
+	require.NoError(t, err)
+	_, _, err = ks.Export("service", "passphrase")
+	assert.ErrorIs(t, err, keystore.ErrWrongPassphrase)
+}
+
+func TestCreateExistingKeystore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	_, err := keystore.Create(path, kdfOptions[keystore.KDFScrypt])
+	require.NoError(t, err)
+
+	_, err = keystore.Create(path, kdfOptions[keystore.KDFScrypt])
+	assert.Error(t, err)
+}
+
+func TestInvalidKDFParameters(t *testing.T) {
+	// key derivation parameters are read from the file and must be validated before deriving keys
+	tests := map[string]struct {
+		kdf    string
+		params map[string]any
+	}{
+		"argon2id zero time":    {keystore.KDFArgon2id, map[string]any{"time": 0, "memory": 1024, "threads": 1}},
+		"argon2id zero threads": {keystore.KDFArgon2id, map[string]any{"time": 1, "memory": 1024, "threads": 0}},
+		"argon2id huge memory":  {keystore.KDFArgon2id, map[string]any{"time": 1, "memory": 1<<32 - 1, "threads": 1}},
+		"scrypt huge n":         {keystore.KDFScrypt, map[string]any{"n": 1 << 40, "r": 8, "p": 1}},
+		"scrypt huge p":         {keystore.KDFScrypt, map[string]any{"n": 1 << 10, "r": 8, "p": 1 << 20}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.json")
+			ks, err := keystore.Create(path, kdfOptions[test.kdf])
+			require.NoError(t, err)
+			require.NoError(t, ks.Import("service", generateKey(t, crypto.ECDSA_P256), crypto.SHA3_256, "passphrase"))
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			var file map[string]any
+			require.NoError(t, json.Unmarshal(content, &file))
+			key := file["keys"].([]any)[0].(map[string]any)
+			key["crypto"].(map[string]any)[test.kdf] = test.params
+			content, err = json.Marshal(file)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, content, 0600))
+
+			ks, err = keystore.Open(path)
+			require.NoError(t, err)
+			_, _, err = ks.Export("service", "passphrase")
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, keystore.ErrWrongPassphrase)
+		})
+	}
+}
+
+func TestFailedSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keystore")
+	require.NoError(t, os.Mkdir(dir, 0700))
+
+	ks, err := keystore.Create(filepath.Join(dir, "keys.json"), kdfOptions[keystore.KDFScrypt])
+	require.NoError(t, err)
+	require.NoError(t, ks.Import("service", generateKey(t, crypto.ECDSA_P256), crypto.SHA3_256, "passphrase"))
+
+	// the store is unchanged when its file can't be written
+	require.NoError(t, os.RemoveAll(dir))
+	assert.Error(t, ks.Delete("service"))
+	assert.Error(t, ks.Import("alice", generateKey(t, crypto.ECDSA_P256), crypto.SHA3_256, "passphrase"))
+
+	infos, err := ks.List()
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "service", infos[0].Name)
+}
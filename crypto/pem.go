@@ -0,0 +1,238 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypto
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// PEM block types of the supported encodings.
+const (
+	pemTypePublicKey       = "PUBLIC KEY"
+	pemTypePrivateKey      = "PRIVATE KEY"
+	pemTypeECPrivateKey    = "EC PRIVATE KEY"
+	ecPrivateKeyVersion    = 1
+	ecdsaPrivateKeyByteLen = 32
+)
+
+// pkcs8 is a PKCS#8 private key (RFC 5208).
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// ecPrivateKey is a SEC1 elliptic curve private key (RFC 5915).
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// EncodePublicKeyPEM encodes an ECDSA public key as a PEM block of a PKIX, ASN.1 DER public key.
+//
+// The function only supports ECDSA with P256 and secp256k1 curves.
+func EncodePublicKeyPEM(pk PublicKey) (string, error) {
+	curveOID, err := namedCurveOID(pk.Algorithm())
+	if err != nil {
+		return "", err
+	}
+
+	der, err := asn1.Marshal(publicKeyInfo{
+		Algorithm: ecdsaAlgorithmIdentifier(curveOID),
+		PublicKey: uncompressedPoint(pk),
+	})
+	if err != nil {
+		return "", fmt.Errorf("crypto: failed to encode public key: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: der})), nil
+}
+
+// EncodePrivateKeyPEM encodes an ECDSA private key as a PEM block of a PKCS#8, ASN.1 DER private key.
+//
+// The function only supports ECDSA with P256 and secp256k1 curves.
+func EncodePrivateKeyPEM(sk PrivateKey) (string, error) {
+	curveOID, err := namedCurveOID(sk.Algorithm())
+	if err != nil {
+		return "", err
+	}
+
+	// the curve is already specified by the PKCS#8 algorithm identifier
+	ecKey, err := marshalECPrivateKey(sk, nil)
+	if err != nil {
+		return "", err
+	}
+	der, err := asn1.Marshal(pkcs8{
+		Algo:       ecdsaAlgorithmIdentifier(curveOID),
+		PrivateKey: ecKey,
+	})
+	if err != nil {
+		return "", fmt.Errorf("crypto: failed to encode private key: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der})), nil
+}
+
+// EncodePrivateKeySEC1PEM encodes an ECDSA private key as a PEM block of a SEC1, ASN.1 DER private key,
+// as output by `openssl ecparam -genkey`.
+//
+// The function only supports ECDSA with P256 and secp256k1 curves.
+func EncodePrivateKeySEC1PEM(sk PrivateKey) (string, error) {
+	curveOID, err := namedCurveOID(sk.Algorithm())
+	if err != nil {
+		return "", err
+	}
+
+	der, err := marshalECPrivateKey(sk, curveOID)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: pemTypeECPrivateKey, Bytes: der})), nil
+}
+
+// DecodePrivateKeyPEM decodes a PEM ECDSA private key with the given curve, encoded in `sigAlgo`.
+//
+// Both PKCS#8 ("PRIVATE KEY") and SEC1 ("EC PRIVATE KEY") blocks are supported. The function
+// returns an error if the curve of the encoded key is not the curve of `sigAlgo`.
+// The function only supports ECDSA with P256 and secp256k1 curves, and unencrypted keys.
+func DecodePrivateKeyPEM(sigAlgo SignatureAlgorithm, s string) (PrivateKey, error) {
+	curveOID, err := namedCurveOID(sigAlgo)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("crypto: failed to parse PEM string, no PEM block found")
+	}
+
+	var keyBytes []byte
+	switch block.Type {
+	case pemTypePrivateKey:
+		keyBytes, err = parsePKCS8PrivateKey(block.Bytes, curveOID)
+	case pemTypeECPrivateKey:
+		keyBytes, err = parseECPrivateKey(block.Bytes, curveOID)
+	default:
+		return nil, fmt.Errorf("crypto: unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// decode the scalar and check the resulting key is valid for the curve
+	return DecodePrivateKey(sigAlgo, keyBytes)
+}
+
+// namedCurveOID returns the object ID of the curve of an ECDSA signature algorithm.
+func namedCurveOID(sigAlgo SignatureAlgorithm) (asn1.ObjectIdentifier, error) {
+	switch sigAlgo {
+	case ECDSA_P256:
+		return oidNamedCurveP256, nil
+	case ECDSA_secp256k1:
+		return oidNamedCurveSECP256K1, nil
+	default:
+		return nil, fmt.Errorf("crypto: only ECDSA algorithms are supported")
+	}
+}
+
+func ecdsaAlgorithmIdentifier(curveOID asn1.ObjectIdentifier) pkix.AlgorithmIdentifier {
+	// marshalling an object ID can't fail
+	params, _ := asn1.Marshal(curveOID)
+	return pkix.AlgorithmIdentifier{
+		Algorithm:  oidPublicKeyECDSA,
+		Parameters: asn1.RawValue{FullBytes: params},
+	}
+}
+
+// uncompressedPoint returns the SEC1 uncompressed encoding of an ECDSA public key.
+func uncompressedPoint(pk PublicKey) asn1.BitString {
+	point := append([]byte{4}, pk.Encode()...)
+	return asn1.BitString{Bytes: point, BitLength: 8 * len(point)}
+}
+
+// marshalECPrivateKey encodes a SEC1 private key, including the curve if `curveOID` is not nil.
+func marshalECPrivateKey(sk PrivateKey, curveOID asn1.ObjectIdentifier) ([]byte, error) {
+	der, err := asn1.Marshal(ecPrivateKey{
+		Version:       ecPrivateKeyVersion,
+		PrivateKey:    sk.Encode(),
+		NamedCurveOID: curveOID,
+		PublicKey:     uncompressedPoint(sk.PublicKey()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("crypto: failed to encode private key: %w", err)
+	}
+	return der, nil
+}
+
+// parsePKCS8PrivateKey parses a PKCS#8 ECDSA private key and returns the raw private scalar.
+func parsePKCS8PrivateKey(der []byte, curveOID asn1.ObjectIdentifier) ([]byte, error) {
+	var key pkcs8
+	if rest, err := asn1.Unmarshal(der, &key); err != nil {
+		return nil, fmt.Errorf("crypto: failed to parse private key: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("crypto: trailing data after ASN.1 of private key")
+	}
+
+	if !key.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, errors.New("crypto: unknown private key algorithm")
+	}
+
+	keyCurveOID := new(asn1.ObjectIdentifier)
+	if _, err := asn1.Unmarshal(key.Algo.Parameters.FullBytes, keyCurveOID); err != nil {
+		return nil, errors.New("crypto: failed to parse ECDSA parameters as named curve")
+	}
+	if !keyCurveOID.Equal(curveOID) {
+		return nil, errors.New("crypto: elliptic curve doesn't match the signature algorithm")
+	}
+
+	return parseECPrivateKey(key.PrivateKey, curveOID)
+}
+
+// parseECPrivateKey parses a SEC1 private key and returns the raw private scalar.
+func parseECPrivateKey(der []byte, curveOID asn1.ObjectIdentifier) ([]byte, error) {
+	var key ecPrivateKey
+	if rest, err := asn1.Unmarshal(der, &key); err != nil {
+		return nil, fmt.Errorf("crypto: failed to parse private key: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("crypto: trailing data after ASN.1 of private key")
+	}
+
+	if key.Version != ecPrivateKeyVersion {
+		return nil, fmt.Errorf("crypto: unknown EC private key version %d", key.Version)
+	}
+	// the curve is optional when the key is embedded in a PKCS#8 key
+	if len(key.NamedCurveOID) > 0 && !key.NamedCurveOID.Equal(curveOID) {
+		return nil, errors.New("crypto: elliptic curve doesn't match the signature algorithm")
+	}
+	if len(key.PrivateKey) > ecdsaPrivateKeyByteLen {
+		return nil, errors.New("crypto: invalid private key length")
+	}
+
+	// some encoders strip the leading zeros of the scalar
+	scalar := make([]byte, ecdsaPrivateKeyByteLen)
+	copy(scalar[ecdsaPrivateKeyByteLen-len(key.PrivateKey):], key.PrivateKey)
+	return scalar, nil
+}
@@ -0,0 +1,320 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pkcs11 provides a PKCS#11 (Cryptoki) implementation of the crypto.Signer interface,
+// for keys held in hardware security modules.
+//
+// The PKCS#11 specification can be found here: https://docs.oasis-open.org/pkcs11/pkcs11-base/v2.40/pkcs11-base-v2.40.html
+package pkcs11
+
+import (
+	"context"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	p11 "github.com/miekg/pkcs11"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// defaultMaxSessions is the default number of concurrent sessions opened with the token.
+const defaultMaxSessions = 8
+
+var (
+	// object IDs of the 2 supported curves (https://www.secg.org/sec2-v2.pdf)
+	oidNamedCurveP256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveSECP256K1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// Config is the configuration of a PKCS#11 client.
+type Config struct {
+	// ModulePath is the path of the PKCS#11 shared library of the HSM vendor.
+	ModulePath string
+	// TokenLabel selects the slot holding the token with this label.
+	TokenLabel string
+	// SlotID selects the slot with this ID. It is only used if TokenLabel is empty.
+	SlotID *uint
+	// PIN is the user PIN of the token.
+	PIN string
+	// MaxSessions is the maximum number of sessions used concurrently for signing.
+	// It defaults to 8 if zero.
+	MaxSessions int
+}
+
+// Key is a reference to an ECDSA key pair stored on the token.
+//
+// Keys are looked up by label, by ID, or both. The private and public key objects
+// must share the same label and ID.
+type Key struct {
+	Label string `json:"label"`
+	ID    []byte `json:"id"`
+	// HashAlgorithm is the hash algorithm used to hash messages before signing.
+	// It defaults to SHA2-256 if unknown.
+	HashAlgorithm crypto.HashAlgorithm `json:"hashAlgorithm"`
+}
+
+// Client is a client for interacting with a PKCS#11 token
+// using types native to the Flow Go SDK.
+type Client struct {
+	ctx      *p11.Ctx
+	slot     uint
+	sessions *sessionPool
+}
+
+// NewClient loads the PKCS#11 module, selects the configured slot and logs in to the token.
+//
+// The client should be closed with `Close` when it is no longer used.
+func NewClient(config Config) (*Client, error) {
+	if config.ModulePath == "" {
+		return nil, errors.New("pkcs11: missing module path")
+	}
+	if config.TokenLabel == "" && config.SlotID == nil {
+		return nil, errors.New("pkcs11: missing token label or slot ID")
+	}
+	maxSessions := config.MaxSessions
+	if maxSessions <= 0 {
+		maxSessions = defaultMaxSessions
+	}
+
+	ctx := p11.New(config.ModulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("pkcs11: failed to load module %s", config.ModulePath)
+	}
+
+	err := ctx.Initialize()
+	if err != nil && !errors.Is(err, p11.Error(p11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("pkcs11: failed to initialize module: %w", err)
+	}
+
+	slot, err := findSlot(ctx, config)
+	if err != nil {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+
+	sessions := newSessionPool(ctx, slot, maxSessions)
+
+	// the login state is shared by all the sessions of the application with the token
+	session, err := sessions.acquire(context.Background())
+	if err != nil {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+		return nil, fmt.Errorf("pkcs11: failed to open session: %w", err)
+	}
+	err = ctx.Login(session, p11.CKU_USER, config.PIN)
+	if err != nil && !errors.Is(err, p11.Error(p11.CKR_USER_ALREADY_LOGGED_IN)) {
+		sessions.close()
+		_ = ctx.Finalize()
+		ctx.Destroy()
+		return nil, fmt.Errorf("pkcs11: failed to login: %w", err)
+	}
+	sessions.release(session)
+
+	return &Client{
+		ctx:      ctx,
+		slot:     slot,
+		sessions: sessions,
+	}, nil
+}
+
+// Close closes all the sessions with the token and unloads the PKCS#11 module.
+func (c *Client) Close() error {
+	c.sessions.close()
+	err := c.ctx.Finalize()
+	c.ctx.Destroy()
+	if err != nil {
+		return fmt.Errorf("pkcs11: failed to finalize module: %w", err)
+	}
+	return nil
+}
+
+// findSlot returns the slot selected by the configuration.
+func findSlot(ctx *p11.Ctx, config Config) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: failed to list slots: %w", err)
+	}
+
+	for _, slot := range slots {
+		if config.TokenLabel == "" {
+			if slot == *config.SlotID {
+				return slot, nil
+			}
+			continue
+		}
+
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("pkcs11: failed to get token info of slot %d: %w", slot, err)
+		}
+		if info.Label == config.TokenLabel {
+			return slot, nil
+		}
+	}
+
+	if config.TokenLabel != "" {
+		return 0, fmt.Errorf("pkcs11: no token found with label %s", config.TokenLabel)
+	}
+	return 0, fmt.Errorf("pkcs11: no token found in slot %d", *config.SlotID)
+}
+
+// GetPublicKey fetches the public key portion of an ECDSA key pair stored on the token.
+//
+// Keys on the curves P-256 and secp256k1 are the only keys supported by the SDK.
+func (c *Client) GetPublicKey(ctx context.Context, key Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+	hashAlgo := parseHashAlgorithm(key.HashAlgorithm)
+
+	session, err := c.sessions.acquire(ctx)
+	if err != nil {
+		return nil, crypto.UnknownHashAlgorithm, fmt.Errorf("pkcs11: failed to open session: %w", err)
+	}
+	defer c.sessions.release(session)
+
+	object, err := c.findObject(session, p11.CKO_PUBLIC_KEY, key)
+	if err != nil {
+		return nil, crypto.UnknownHashAlgorithm, err
+	}
+
+	attributes, err := c.ctx.GetAttributeValue(session, object, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_EC_PARAMS, nil),
+		p11.NewAttribute(p11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("pkcs11: failed to read public key attributes: %w", err)
+	}
+
+	sigAlgo := parseSignatureAlgorithm(attributes[0].Value)
+	if sigAlgo == crypto.UnknownSignatureAlgorithm {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("pkcs11: unsupported elliptic curve parameters %x", attributes[0].Value)
+	}
+
+	if !crypto.CompatibleAlgorithms(sigAlgo, hashAlgo) {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("pkcs11: unsupported hash algorithm %s", hashAlgo)
+	}
+
+	point, err := parseECPoint(attributes[1].Value)
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("pkcs11: failed to parse public key: %w", err)
+	}
+
+	publicKey, err := crypto.DecodePublicKey(sigAlgo, point)
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("pkcs11: failed to decode public key: %w", err)
+	}
+
+	return publicKey, hashAlgo, nil
+}
+
+// findObject returns the unique EC key object of the given class matching the key reference.
+func (c *Client) findObject(session p11.SessionHandle, class uint, key Key) (p11.ObjectHandle, error) {
+	if key.Label == "" && len(key.ID) == 0 {
+		return 0, errors.New("pkcs11: missing key label or ID")
+	}
+
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, class),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_EC),
+	}
+	if key.Label != "" {
+		template = append(template, p11.NewAttribute(p11.CKA_LABEL, key.Label))
+	}
+	if len(key.ID) > 0 {
+		template = append(template, p11.NewAttribute(p11.CKA_ID, key.ID))
+	}
+
+	if err := c.ctx.FindObjectsInit(session, template); err != nil {
+		return 0, fmt.Errorf("pkcs11: failed to search key: %w", err)
+	}
+	objects, _, err := c.ctx.FindObjects(session, 2)
+	finalErr := c.ctx.FindObjectsFinal(session)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: failed to search key: %w", err)
+	}
+	if finalErr != nil {
+		return 0, fmt.Errorf("pkcs11: failed to search key: %w", finalErr)
+	}
+
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("pkcs11: key not found (label: %q, id: %x)", key.Label, key.ID)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("pkcs11: several keys found (label: %q, id: %x)", key.Label, key.ID)
+	}
+}
+
+// parseSignatureAlgorithm returns the `SignatureAlgorithm` corresponding to the input
+// DER encoded EC parameters (CKA_EC_PARAMS).
+func parseSignatureAlgorithm(ecParams []byte) crypto.SignatureAlgorithm {
+	var namedCurveOID asn1.ObjectIdentifier
+	rest, err := asn1.Unmarshal(ecParams, &namedCurveOID)
+	if err != nil || len(rest) != 0 {
+		return crypto.UnknownSignatureAlgorithm
+	}
+
+	if namedCurveOID.Equal(oidNamedCurveP256) {
+		return crypto.ECDSA_P256
+	}
+
+	if namedCurveOID.Equal(oidNamedCurveSECP256K1) {
+		return crypto.ECDSA_secp256k1
+	}
+
+	return crypto.UnknownSignatureAlgorithm
+}
+
+// parseHashAlgorithm returns the hash algorithm of the key, defaulting to SHA2-256.
+//
+// Messages are hashed outside the token, any hash algorithm compatible with ECDSA can be used.
+func parseHashAlgorithm(hashAlgo crypto.HashAlgorithm) crypto.HashAlgorithm {
+	if hashAlgo == crypto.UnknownHashAlgorithm {
+		return crypto.SHA2_256
+	}
+	return hashAlgo
+}
+
+// parseECPoint returns the raw X||Y encoding of an uncompressed EC point (CKA_EC_POINT).
+//
+// The specification requires the point to be wrapped in a DER octet string, but some
+// modules return the point directly.
+func parseECPoint(ecPoint []byte) ([]byte, error) {
+	point := ecPoint
+	var wrapped []byte
+	if rest, err := asn1.Unmarshal(ecPoint, &wrapped); err == nil && len(rest) == 0 {
+		point = wrapped
+	}
+
+	if len(point) == 0 || point[0] != 4 { // uncompressed form
+		return nil, errors.New("only uncompressed points are supported")
+	}
+	return point[1:], nil
+}
@@ -0,0 +1,213 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkcs11_test
+
+import (
+	"context"
+	"encoding/asn1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	p11 "github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/pkcs11"
+)
+
+const (
+	tokenLabel = "flow-test"
+	soPIN      = "5678"
+	userPIN    = "1234"
+)
+
+// softHSMModules are the usual install paths of the SoftHSM2 module.
+var softHSMModules = []string{
+	"/usr/lib/softhsm/libsofthsm2.so",
+	"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/local/lib/softhsm/libsofthsm2.so",
+	"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+}
+
+// softHSMModule returns the path of the SoftHSM2 module, or skips the test if SoftHSM2 is not installed.
+//
+// The path can be set with the PKCS11_TEST_MODULE env var.
+func softHSMModule(t *testing.T) string {
+	if module := os.Getenv("PKCS11_TEST_MODULE"); module != "" {
+		return module
+	}
+	for _, module := range softHSMModules {
+		if _, err := os.Stat(module); err == nil {
+			return module
+		}
+	}
+	t.Skip("SoftHSM2 is not installed, set PKCS11_TEST_MODULE to the path of a SoftHSM2 module")
+	return ""
+}
+
+var curveParams = map[crypto.SignatureAlgorithm]asn1.ObjectIdentifier{
+	crypto.ECDSA_P256:      {1, 2, 840, 10045, 3, 1, 7},
+	crypto.ECDSA_secp256k1: {1, 3, 132, 0, 10},
+}
+
+// setupToken initializes a SoftHSM2 token in a temporary directory and generates
+// a key pair labelled with the name of each curve.
+func setupToken(t *testing.T, module string) {
+	dir := t.TempDir()
+	tokenDir := filepath.Join(dir, "tokens")
+	require.NoError(t, os.Mkdir(tokenDir, 0700))
+	conf := filepath.Join(dir, "softhsm2.conf")
+	require.NoError(t, os.WriteFile(conf, []byte(fmt.Sprintf("directories.tokendir = %s\n", tokenDir)), 0600))
+	t.Setenv("SOFTHSM2_CONF", conf)
+
+	ctx := p11.New(module)
+	require.NotNil(t, ctx)
+	defer ctx.Destroy()
+	require.NoError(t, ctx.Initialize())
+	defer func() { _ = ctx.Finalize() }()
+
+	slots, err := ctx.GetSlotList(true)
+	require.NoError(t, err)
+	require.NotEmpty(t, slots)
+	slot := slots[0]
+	require.NoError(t, ctx.InitToken(slot, soPIN, tokenLabel))
+
+	// the token is moved to a new slot once initialized
+	slots, err = ctx.GetSlotList(true)
+	require.NoError(t, err)
+	for _, s := range slots {
+		info, err := ctx.GetTokenInfo(s)
+		require.NoError(t, err)
+		if info.Label == tokenLabel {
+			slot = s
+		}
+	}
+
+	session, err := ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Login(session, p11.CKU_SO, soPIN))
+	require.NoError(t, ctx.InitPIN(session, userPIN))
+	require.NoError(t, ctx.Logout(session))
+	require.NoError(t, ctx.Login(session, p11.CKU_USER, userPIN))
+
+	for sigAlgo, oid := range curveParams {
+		params, err := asn1.Marshal(oid)
+		require.NoError(t, err)
+		_, _, err = ctx.GenerateKeyPair(session,
+			[]*p11.Mechanism{p11.NewMechanism(p11.CKM_EC_KEY_PAIR_GEN, nil)},
+			[]*p11.Attribute{
+				p11.NewAttribute(p11.CKA_TOKEN, true),
+				p11.NewAttribute(p11.CKA_VERIFY, true),
+				p11.NewAttribute(p11.CKA_EC_PARAMS, params),
+				p11.NewAttribute(p11.CKA_LABEL, sigAlgo.String()),
+			},
+			[]*p11.Attribute{
+				p11.NewAttribute(p11.CKA_TOKEN, true),
+				p11.NewAttribute(p11.CKA_PRIVATE, true),
+				p11.NewAttribute(p11.CKA_SIGN, true),
+				p11.NewAttribute(p11.CKA_SENSITIVE, true),
+				p11.NewAttribute(p11.CKA_LABEL, sigAlgo.String()),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, ctx.CloseAllSessions(slot))
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("Missing module path", func(t *testing.T) {
+		_, err := pkcs11.NewClient(pkcs11.Config{TokenLabel: tokenLabel})
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing slot selection", func(t *testing.T) {
+		_, err := pkcs11.NewClient(pkcs11.Config{ModulePath: "/path/to/module.so"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid module", func(t *testing.T) {
+		_, err := pkcs11.NewClient(pkcs11.Config{ModulePath: "/path/to/module.so", TokenLabel: tokenLabel})
+		assert.Error(t, err)
+	})
+}
+
+// TestSoftHSMSigning tests signing with keys generated on a SoftHSM2 token.
+// The test is skipped if SoftHSM2 is not installed.
+func TestSoftHSMSigning(t *testing.T) {
+	module := softHSMModule(t)
+	setupToken(t, module)
+
+	client, err := pkcs11.NewClient(pkcs11.Config{
+		ModulePath:  module,
+		TokenLabel:  tokenLabel,
+		PIN:         userPIN,
+		MaxSessions: 4,
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, client.Close())
+	}()
+
+	ctx := context.Background()
+
+	for sigAlgo := range curveParams {
+		for _, hashAlgo := range []crypto.HashAlgorithm{crypto.SHA2_256, crypto.SHA3_256} {
+			t.Run(fmt.Sprintf("%s %s", sigAlgo, hashAlgo), func(t *testing.T) {
+				key := pkcs11.Key{Label: sigAlgo.String(), HashAlgorithm: hashAlgo}
+
+				pk, keyHashAlgo, err := client.GetPublicKey(ctx, key)
+				require.NoError(t, err)
+				assert.Equal(t, sigAlgo, pk.Algorithm())
+				assert.Equal(t, hashAlgo, keyHashAlgo)
+
+				signer, err := client.SignerForKey(ctx, key)
+				require.NoError(t, err)
+
+				// sign concurrently with more goroutines than sessions
+				var wg sync.WaitGroup
+				for i := 0; i < 16; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						msg := []byte(fmt.Sprintf("random_message_%d", i))
+						sig, err := signer.Sign(msg)
+						assert.NoError(t, err)
+
+						hasher, err := crypto.NewHasher(hashAlgo)
+						assert.NoError(t, err)
+						valid, err := pk.Verify(sig, msg, hasher)
+						assert.NoError(t, err)
+						assert.True(t, valid)
+					}(i)
+				}
+				wg.Wait()
+			})
+		}
+	}
+
+	t.Run("Unknown key", func(t *testing.T) {
+		_, err := client.SignerForKey(ctx, pkcs11.Key{Label: "unknown"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkcs11
+
+import (
+	"context"
+
+	p11 "github.com/miekg/pkcs11"
+)
+
+// sessionPool is a bounded pool of sessions with a token.
+//
+// A PKCS#11 session can only run one operation at a time, the pool allows
+// signing concurrently with up to a maximum number of sessions.
+type sessionPool struct {
+	ctx  *p11.Ctx
+	slot uint
+	// idle holds the open sessions not currently in use
+	idle chan p11.SessionHandle
+	// slots limits the number of sessions in use
+	slots chan struct{}
+}
+
+func newSessionPool(ctx *p11.Ctx, slot uint, maxSessions int) *sessionPool {
+	return &sessionPool{
+		ctx:   ctx,
+		slot:  slot,
+		idle:  make(chan p11.SessionHandle, maxSessions),
+		slots: make(chan struct{}, maxSessions),
+	}
+}
+
+// acquire returns a session for exclusive use, opening a new one if no session is idle.
+//
+// It blocks until a session is available or the context is done.
+func (p *sessionPool) acquire(ctx context.Context) (p11.SessionHandle, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	select {
+	case session := <-p.idle:
+		return session, nil
+	default:
+	}
+
+	session, err := p.ctx.OpenSession(p.slot, p11.CKF_SERIAL_SESSION)
+	if err != nil {
+		<-p.slots
+		return 0, err
+	}
+	return session, nil
+}
+
+// release returns a session to the pool.
+//
+// Sessions are never closed before the pool, since the login state of the
+// application is lost once all its sessions are closed.
+func (p *sessionPool) release(session p11.SessionHandle) {
+	p.idle <- session
+	<-p.slots
+}
+
+// close closes all the sessions with the token.
+func (p *sessionPool) close() {
+	_ = p.ctx.CloseAllSessions(p.slot)
+}
@@ -0,0 +1,128 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkcs11
+
+import (
+	"context"
+	"fmt"
+
+	p11 "github.com/miekg/pkcs11"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/internal"
+)
+
+var _ crypto.ContextSigner = (*Signer)(nil)
+
+// Signer is a PKCS#11 implementation of crypto.Signer and crypto.ContextSigner.
+//
+// A Signer is safe for concurrent use, signing requests are spread over the sessions of the client.
+type Signer struct {
+	ctx    context.Context
+	client *Client
+	key    Key
+	// handle of the private key object, valid in all the sessions of the client
+	privateKey p11.ObjectHandle
+	// ECDSA is the only algorithm supported by this package. The signature algorithm
+	// therefore represents the elliptic curve used. The curve is needed to parse the token signature.
+	curve crypto.SignatureAlgorithm
+	// public key for easier access
+	publicKey crypto.PublicKey
+	// Hash algorithm used to hash messages before signing
+	hashAlgo crypto.HashAlgorithm
+}
+
+// SignerForKey returns a new PKCS#11 signer for an ECDSA key pair stored on the token.
+//
+// Only ECDSA keys on P-256 and secp256k1 curves are supported.
+func (c *Client) SignerForKey(
+	ctx context.Context,
+	key Key,
+) (*Signer, error) {
+	pk, hashAlgo, err := c.GetPublicKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := c.sessions.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: failed to open session: %w", err)
+	}
+	defer c.sessions.release(session)
+
+	privateKey, err := c.findObject(session, p11.CKO_PRIVATE_KEY, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		ctx:        ctx,
+		client:     c,
+		key:        key,
+		privateKey: privateKey,
+		curve:      pk.Algorithm(),
+		publicKey:  pk,
+		hashAlgo:   hashAlgo,
+	}, nil
+}
+
+// Sign signs the given message using the token key for this signer.
+//
+// The request is bound to the context passed to `SignerForKey`.
+// Use `SignContext` to bind each request to its own context.
+func (s *Signer) Sign(message []byte) ([]byte, error) {
+	return s.SignContext(s.ctx, message)
+}
+
+// SignContext signs the given message using the token key for this signer.
+//
+// The message is hashed outside the token. The context bounds the wait for a free session,
+// the signing operation itself can't be interrupted once started.
+func (s *Signer) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	hasher, err := crypto.NewHasher(s.hashAlgo)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: failed to sign: %w", err)
+	}
+	digest := hasher.ComputeHash(message)
+
+	session, err := s.client.sessions.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: failed to sign: %w", err)
+	}
+	defer s.client.sessions.release(session)
+
+	err = s.client.ctx.SignInit(session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_ECDSA, nil)}, s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: failed to sign: %w", err)
+	}
+	result, err := s.client.ctx.Sign(session, digest)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: failed to sign: %w", err)
+	}
+
+	sig, err := internal.NormalizeECDSASignature(result, s.curve)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: failed to parse signature: %w", err)
+	}
+	return sig, nil
+}
+
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.publicKey
+}
@@ -0,0 +1,124 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// MessageKind is the kind of a signed message.
+type MessageKind string
+
+const (
+	// KindTransactionPayload is a transaction payload message, signed by proposers and authorizers.
+	KindTransactionPayload MessageKind = "transaction-payload"
+	// KindTransactionEnvelope is a transaction envelope message, signed by payers.
+	KindTransactionEnvelope MessageKind = "transaction-envelope"
+	// KindUserMessage is a user message signed in the user domain.
+	KindUserMessage MessageKind = "user-message"
+	// KindUnknown is a message without a known domain tag.
+	KindUnknown MessageKind = "unknown"
+)
+
+// Message is a decoded signed message.
+type Message struct {
+	Kind MessageKind
+	// Raw is the message to sign, including its domain tag.
+	Raw []byte
+	// Transaction is the decoded transaction of payload and envelope messages.
+	Transaction *flow.Transaction
+	// UserMessage is the content of user messages, without the domain tag.
+	UserMessage []byte
+}
+
+// IsTransaction returns true if the message is a transaction payload or envelope.
+func (m Message) IsTransaction() bool {
+	return m.Kind == KindTransactionPayload || m.Kind == KindTransactionEnvelope
+}
+
+// DecodeMessage decodes a message to sign, recognizing the transaction and user domains.
+//
+// Messages without a known domain tag are returned with the kind KindUnknown.
+// An error is returned if a message with the transaction domain tag can't be decoded.
+func DecodeMessage(message []byte) (Message, error) {
+	switch {
+	case bytes.HasPrefix(message, flow.TransactionDomainTag[:]):
+		encoded := message[len(flow.TransactionDomainTag):]
+
+		kind, err := transactionMessageKind(encoded)
+		if err != nil {
+			return Message{}, fmt.Errorf("policy: failed to decode transaction message: %w", err)
+		}
+		tx, err := flow.DecodeTransaction(encoded)
+		if err != nil {
+			return Message{}, fmt.Errorf("policy: failed to decode transaction message: %w", err)
+		}
+
+		return Message{
+			Kind:        kind,
+			Raw:         message,
+			Transaction: tx,
+		}, nil
+
+	case bytes.HasPrefix(message, flow.UserDomainTag[:]):
+		return Message{
+			Kind:        KindUserMessage,
+			Raw:         message,
+			UserMessage: message[len(flow.UserDomainTag):],
+		}, nil
+
+	default:
+		return Message{
+			Kind: KindUnknown,
+			Raw:  message,
+		}, nil
+	}
+}
+
+// ScriptHash returns the SHA3-256 hash of a Cadence script, used to allowlist transaction templates.
+func ScriptHash(script []byte) crypto.Hash {
+	return crypto.NewSHA3_256().ComputeHash(script)
+}
+
+// transactionMessageKind distinguishes payload and envelope messages: the first element
+// of a payload is the script, while the first element of an envelope is the payload list.
+func transactionMessageKind(encoded []byte) (MessageKind, error) {
+	kind, content, _, err := rlp.Split(encoded)
+	if err != nil {
+		return KindUnknown, err
+	}
+	if kind != rlp.List {
+		return KindUnknown, fmt.Errorf("unexpected rlp decoding type")
+	}
+
+	kind, _, _, err = rlp.Split(content)
+	if err != nil {
+		return KindUnknown, err
+	}
+	if kind == rlp.List {
+		return KindTransactionEnvelope, nil
+	}
+	return KindTransactionPayload, nil
+}
@@ -0,0 +1,252 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy_test
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/policy"
+)
+
+const transferScript = `transaction(amount: UFix64, to: Address) { prepare(signer: &Account) {} }`
+
+var (
+	custody = flow.HexToAddress("01")
+	payer   = flow.HexToAddress("02")
+	foreign = flow.HexToAddress("03")
+)
+
+func newSigner(t *testing.T) crypto.InMemorySigner {
+	seed := make([]byte, crypto.MinSeedLength)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	sk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, seed)
+	require.NoError(t, err)
+	signer, err := crypto.NewInMemorySigner(sk, crypto.SHA3_256)
+	require.NoError(t, err)
+	return signer
+}
+
+var errSigning = errors.New("signing failed")
+
+// failingSigner is a signer whose signing always fails.
+type failingSigner struct {
+	crypto.Signer
+}
+
+func (failingSigner) Sign([]byte) ([]byte, error) {
+	return nil, errSigning
+}
+
+func newTransfer(t *testing.T, amount string, payerAddress flow.Address) *flow.Transaction {
+	value, err := cadence.NewUFix64(amount)
+	require.NoError(t, err)
+
+	tx := flow.NewTransaction().
+		SetScript([]byte(transferScript)).
+		SetComputeLimit(100).
+		SetProposalKey(custody, 0, 0).
+		SetPayer(payerAddress).
+		AddAuthorizer(custody)
+	require.NoError(t, tx.AddArgument(value))
+	require.NoError(t, tx.AddArgument(cadence.NewAddress(foreign)))
+	return tx
+}
+
+func TestDecodeMessage(t *testing.T) {
+	tx := newTransfer(t, "10.0", payer)
+
+	payload, err := policy.DecodeMessage(append(flow.TransactionDomainTag[:], tx.PayloadMessage()...))
+	require.NoError(t, err)
+	assert.Equal(t, policy.KindTransactionPayload, payload.Kind)
+	assert.Equal(t, tx.Script, payload.Transaction.Script)
+	assert.Equal(t, payer, payload.Transaction.Payer)
+
+	tx.AddPayloadSignature(custody, 0, []byte{1, 2, 3})
+	envelope, err := policy.DecodeMessage(append(flow.TransactionDomainTag[:], tx.EnvelopeMessage()...))
+	require.NoError(t, err)
+	assert.Equal(t, policy.KindTransactionEnvelope, envelope.Kind)
+	assert.Equal(t, tx.Authorizers, envelope.Transaction.Authorizers)
+
+	user, err := policy.DecodeMessage(append(flow.UserDomainTag[:], "hello"...))
+	require.NoError(t, err)
+	assert.Equal(t, policy.KindUserMessage, user.Kind)
+	assert.Equal(t, []byte("hello"), user.UserMessage)
+
+	unknown, err := policy.DecodeMessage([]byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, policy.KindUnknown, unknown.Kind)
+
+	_, err = policy.DecodeMessage(append(flow.TransactionDomainTag[:], 1, 2, 3))
+	assert.Error(t, err)
+}
+
+func TestSigner(t *testing.T) {
+	maxAmount, err := cadence.NewUFix64("100.0")
+	require.NoError(t, err)
+
+	var records []policy.AuditRecord
+	inner := newSigner(t)
+	signer := policy.NewSigner(
+		inner,
+		[]policy.Rule{
+			policy.AllowKinds(policy.KindTransactionPayload, policy.KindTransactionEnvelope),
+			policy.AllowScripts(policy.ScriptHash([]byte(transferScript))),
+			policy.AllowPayers(custody, payer),
+			policy.AllowAuthorizers(custody),
+			policy.MaxComputeLimit(1000),
+			policy.MaxUFix64Argument(0, maxAmount),
+		},
+		policy.WithAudit(func(record policy.AuditRecord) {
+			records = append(records, record)
+		}),
+	)
+	assert.True(t, inner.PublicKey().Equals(signer.PublicKey()))
+
+	t.Run("Allowed transaction", func(t *testing.T) {
+		records = nil
+		tx := newTransfer(t, "10.0", payer)
+		require.NoError(t, tx.SignPayload(custody, 0, signer))
+
+		message := append(flow.TransactionDomainTag[:], tx.PayloadMessage()...)
+		valid, err := inner.PublicKey().Verify(tx.PayloadSignatures[0].Signature, message, crypto.NewSHA3_256())
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		require.Len(t, records, 1)
+		assert.True(t, records[0].Allowed)
+		assert.Equal(t, policy.KindTransactionPayload, records[0].Kind)
+		assert.Equal(t, policy.ScriptHash([]byte(transferScript)), records[0].ScriptHash)
+		assert.Equal(t, payer, records[0].Payer)
+		assert.Equal(t, uint64(100), records[0].ComputeLimit)
+	})
+
+	denied := []struct {
+		name string
+		rule string
+		tx   func(t *testing.T) *flow.Transaction
+	}{
+		{
+			name: "Foreign payer",
+			rule: "allow-payers",
+			tx: func(t *testing.T) *flow.Transaction {
+				return newTransfer(t, "10.0", foreign)
+			},
+		},
+		{
+			name: "Amount over limit",
+			rule: "max-ufix64-argument",
+			tx: func(t *testing.T) *flow.Transaction {
+				return newTransfer(t, "100.5", payer)
+			},
+		},
+		{
+			name: "Unknown script",
+			rule: "allow-scripts",
+			tx: func(t *testing.T) *flow.Transaction {
+				return newTransfer(t, "10.0", payer).SetScript([]byte(`transaction {}`))
+			},
+		},
+		{
+			name: "Foreign authorizer",
+			rule: "allow-authorizers",
+			tx: func(t *testing.T) *flow.Transaction {
+				return newTransfer(t, "10.0", payer).AddAuthorizer(foreign)
+			},
+		},
+		{
+			name: "Compute limit over cap",
+			rule: "max-compute-limit",
+			tx: func(t *testing.T) *flow.Transaction {
+				return newTransfer(t, "10.0", payer).SetComputeLimit(9999)
+			},
+		},
+	}
+
+	for _, test := range denied {
+		t.Run(test.name, func(t *testing.T) {
+			records = nil
+			err := test.tx(t).SignEnvelope(payer, 0, signer)
+			assert.ErrorIs(t, err, policy.ErrDenied)
+
+			require.Len(t, records, 1)
+			assert.False(t, records[0].Allowed)
+			assert.Equal(t, policy.KindTransactionEnvelope, records[0].Kind)
+			assert.Equal(t, test.rule, records[0].Rule)
+			assert.NotEmpty(t, records[0].Reason)
+		})
+	}
+
+	t.Run("Denied user message", func(t *testing.T) {
+		records = nil
+		_, err := flow.SignUserMessage(signer, []byte("hello"))
+		assert.ErrorIs(t, err, policy.ErrDenied)
+		require.Len(t, records, 1)
+		assert.False(t, records[0].Allowed)
+		assert.Equal(t, policy.KindUserMessage, records[0].Kind)
+
+		// transaction rules don't allow user messages
+		scripts := policy.NewSigner(inner, []policy.Rule{policy.AllowScripts(policy.ScriptHash([]byte(transferScript)))})
+		_, err = flow.SignUserMessage(scripts, []byte("hello"))
+		assert.ErrorIs(t, err, policy.ErrDenied)
+
+		permissive := policy.NewSigner(inner, nil, policy.WithUserMessages())
+		_, err = flow.SignUserMessage(permissive, []byte("hello"))
+		assert.NoError(t, err)
+
+		kinds := policy.NewSigner(inner, []policy.Rule{policy.AllowKinds(policy.KindTransactionPayload)}, policy.WithUserMessages())
+		_, err = flow.SignUserMessage(kinds, []byte("hello"))
+		assert.ErrorIs(t, err, policy.ErrDenied)
+	})
+
+	t.Run("Signing failure", func(t *testing.T) {
+		var records []policy.AuditRecord
+		failing := policy.NewSigner(
+			failingSigner{inner},
+			nil,
+			policy.WithAudit(func(record policy.AuditRecord) {
+				records = append(records, record)
+			}),
+		)
+
+		err := newTransfer(t, "10.0", payer).SignEnvelope(payer, 0, failing)
+		assert.ErrorIs(t, err, errSigning)
+		require.Len(t, records, 1)
+		assert.False(t, records[0].Allowed)
+		assert.Empty(t, records[0].Rule)
+		assert.Equal(t, errSigning.Error(), records[0].Error)
+	})
+
+	t.Run("Unknown messages", func(t *testing.T) {
+		_, err := signer.Sign([]byte("hello"))
+		assert.ErrorIs(t, err, policy.ErrDenied)
+
+		permissive := policy.NewSigner(inner, nil, policy.WithUnknownMessages())
+		_, err = permissive.Sign([]byte("hello"))
+		assert.NoError(t, err)
+	})
+}
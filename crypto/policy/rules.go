@@ -0,0 +1,153 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/onflow/cadence"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// Rule is a check evaluated over a decoded message before it is signed.
+type Rule interface {
+	// Name identifies the rule in audit records.
+	Name() string
+	// Check returns an error if the message must not be signed.
+	Check(message Message) error
+}
+
+type ruleFunc struct {
+	name  string
+	check func(message Message) error
+}
+
+func (r ruleFunc) Name() string {
+	return r.name
+}
+
+func (r ruleFunc) Check(message Message) error {
+	return r.check(message)
+}
+
+// NewRule returns a rule with the given name, checking messages with the given function.
+func NewRule(name string, check func(message Message) error) Rule {
+	return ruleFunc{name: name, check: check}
+}
+
+// transactionRule returns a rule only checking transaction messages, other messages are allowed.
+//
+// User messages and messages without a known domain tag are denied by the signer unless
+// enabled with WithUserMessages and WithUnknownMessages.
+func transactionRule(name string, check func(tx *flow.Transaction) error) Rule {
+	return NewRule(name, func(message Message) error {
+		if !message.IsTransaction() {
+			return nil
+		}
+		return check(message.Transaction)
+	})
+}
+
+// AllowKinds only allows messages of the given kinds.
+func AllowKinds(kinds ...MessageKind) Rule {
+	return NewRule("allow-kinds", func(message Message) error {
+		if !slices.Contains(kinds, message.Kind) {
+			return fmt.Errorf("message kind %s is not allowed", message.Kind)
+		}
+		return nil
+	})
+}
+
+// AllowScripts only allows transactions whose script hash, as computed by ScriptHash,
+// is one of the given hashes.
+func AllowScripts(hashes ...crypto.Hash) Rule {
+	return transactionRule("allow-scripts", func(tx *flow.Transaction) error {
+		hash := ScriptHash(tx.Script)
+		for _, allowed := range hashes {
+			if bytes.Equal(hash, allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("script %s is not allowed", hash.Hex())
+	})
+}
+
+// AllowPayers only allows transactions paid by one of the given addresses.
+func AllowPayers(payers ...flow.Address) Rule {
+	return transactionRule("allow-payers", func(tx *flow.Transaction) error {
+		if !slices.Contains(payers, tx.Payer) {
+			return fmt.Errorf("payer %s is not allowed", tx.Payer)
+		}
+		return nil
+	})
+}
+
+// AllowAuthorizers only allows transactions whose authorizers are all in the given set.
+func AllowAuthorizers(authorizers ...flow.Address) Rule {
+	return transactionRule("allow-authorizers", func(tx *flow.Transaction) error {
+		for _, authorizer := range tx.Authorizers {
+			if !slices.Contains(authorizers, authorizer) {
+				return fmt.Errorf("authorizer %s is not allowed", authorizer)
+			}
+		}
+		return nil
+	})
+}
+
+// MaxComputeLimit only allows transactions with a compute limit lower or equal to the given limit.
+func MaxComputeLimit(limit uint64) Rule {
+	return transactionRule("max-compute-limit", func(tx *flow.Transaction) error {
+		if tx.GasLimit > limit {
+			return fmt.Errorf("compute limit %d exceeds %d", tx.GasLimit, limit)
+		}
+		return nil
+	})
+}
+
+// ArgumentConstraint checks the decoded transaction argument at the given index.
+//
+// Transactions without an argument at the index are rejected.
+func ArgumentConstraint(name string, index int, check func(value cadence.Value) error) Rule {
+	return transactionRule(name, func(tx *flow.Transaction) error {
+		value, err := tx.Argument(index)
+		if err != nil {
+			return err
+		}
+		return check(value)
+	})
+}
+
+// MaxUFix64Argument only allows transactions whose UFix64 argument at the given index,
+// for instance a transferred amount, is lower or equal to the given maximum.
+func MaxUFix64Argument(index int, max cadence.UFix64) Rule {
+	return ArgumentConstraint("max-ufix64-argument", index, func(value cadence.Value) error {
+		amount, ok := value.(cadence.UFix64)
+		if !ok {
+			return fmt.Errorf("argument %d is not a UFix64", index)
+		}
+		if amount > max {
+			return fmt.Errorf("argument %d value %s exceeds %s", index, amount, max)
+		}
+		return nil
+	})
+}
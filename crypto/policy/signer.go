@@ -0,0 +1,196 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package policy provides a crypto.Signer wrapper refusing to sign messages
+// that don't satisfy a set of rules.
+//
+// Messages are decoded before signing: transaction payload and envelope messages are decoded
+// into a flow.Transaction, so that rules can check the script, arguments, payer, authorizers
+// and compute limit of the transaction. User messages and messages without a known domain
+// tag are denied unless explicitly allowed. Each signing decision produces an audit record.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// ErrDenied is returned when a message is denied by the policy.
+var ErrDenied = errors.New("policy: signing denied")
+
+// AuditRecord describes a signing decision.
+type AuditRecord struct {
+	Time      time.Time
+	PublicKey crypto.PublicKey
+	Kind      MessageKind
+	// MessageHash is the SHA3-256 hash of the message, including its domain tag.
+	MessageHash crypto.Hash
+	// Allowed is true if the message was signed.
+	Allowed bool
+	// Rule is the name of the rule denying the message, if any.
+	Rule string
+	// Reason is the reason of a denial.
+	Reason string
+	// Error is the error of the wrapped signer, if the message was allowed but signing failed.
+	Error string
+
+	// Transaction fields, set for transaction messages only.
+	ScriptHash   crypto.Hash
+	Payer        flow.Address
+	Authorizers  []flow.Address
+	ComputeLimit uint64
+}
+
+// AuditFunc receives the audit record of each signing decision.
+type AuditFunc func(record AuditRecord)
+
+var _ crypto.ContextSigner = (*Signer)(nil)
+
+// Signer is a crypto.Signer and crypto.ContextSigner only signing messages allowed by its rules.
+type Signer struct {
+	signer            crypto.ContextSigner
+	rules             []Rule
+	audit             AuditFunc
+	allowUnknown      bool
+	allowUserMessages bool
+	now               func() time.Time
+}
+
+// Option is a configuration option for the signer.
+type Option func(*Signer)
+
+// WithAudit sets the function receiving the audit records of the signer.
+func WithAudit(audit AuditFunc) Option {
+	return func(s *Signer) {
+		s.audit = audit
+	}
+}
+
+// WithUnknownMessages allows signing messages without a known domain tag, provided the rules
+// allow them. By default, such messages are denied since they can't be decoded.
+func WithUnknownMessages() Option {
+	return func(s *Signer) {
+		s.allowUnknown = true
+	}
+}
+
+// WithUserMessages allows signing user messages, provided the rules allow them. By default,
+// user messages are denied since transaction rules don't apply to them.
+func WithUserMessages() Option {
+	return func(s *Signer) {
+		s.allowUserMessages = true
+	}
+}
+
+// NewSigner returns a signer wrapping the given signer, only signing messages allowed by all the rules.
+//
+// Rules are evaluated in order, and the first rule denying a message stops the evaluation.
+func NewSigner(signer crypto.Signer, rules []Rule, opts ...Option) *Signer {
+	s := &Signer{
+		signer: crypto.NewContextSigner(signer),
+		rules:  rules,
+		now:    time.Now,
+	}
+	for _, apply := range opts {
+		apply(s)
+	}
+	return s
+}
+
+// Sign signs the given message if it is allowed by the rules of the signer.
+func (s *Signer) Sign(message []byte) ([]byte, error) {
+	return s.SignContext(context.Background(), message)
+}
+
+// SignContext signs the given message if it is allowed by the rules of the signer.
+//
+// The error returned for denied messages wraps ErrDenied.
+func (s *Signer) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	record := AuditRecord{
+		Time:        s.now(),
+		PublicKey:   s.signer.PublicKey(),
+		Kind:        KindUnknown,
+		MessageHash: crypto.NewSHA3_256().ComputeHash(message),
+	}
+
+	if err := s.evaluate(message, &record); err != nil {
+		s.record(record)
+		return nil, err
+	}
+
+	signature, err := s.signer.SignContext(ctx, message)
+	if err != nil {
+		record.Error = err.Error()
+		s.record(record)
+		return nil, err
+	}
+
+	record.Allowed = true
+	s.record(record)
+	return signature, nil
+}
+
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.signer.PublicKey()
+}
+
+// evaluate checks the message against the rules and fills the audit record.
+func (s *Signer) evaluate(message []byte, record *AuditRecord) error {
+	decoded, err := DecodeMessage(message)
+	if err != nil {
+		record.Reason = err.Error()
+		return fmt.Errorf("%w: %w", ErrDenied, err)
+	}
+
+	record.Kind = decoded.Kind
+	if tx := decoded.Transaction; tx != nil {
+		record.ScriptHash = ScriptHash(tx.Script)
+		record.Payer = tx.Payer
+		record.Authorizers = tx.Authorizers
+		record.ComputeLimit = tx.GasLimit
+	}
+
+	if decoded.Kind == KindUnknown && !s.allowUnknown {
+		record.Reason = "unknown message domain"
+		return fmt.Errorf("%w: unknown message domain", ErrDenied)
+	}
+	if decoded.Kind == KindUserMessage && !s.allowUserMessages {
+		record.Reason = "user message"
+		return fmt.Errorf("%w: user message", ErrDenied)
+	}
+
+	for _, rule := range s.rules {
+		if err := rule.Check(decoded); err != nil {
+			record.Rule = rule.Name()
+			record.Reason = err.Error()
+			return fmt.Errorf("%w by rule %s: %w", ErrDenied, rule.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (s *Signer) record(record AuditRecord) {
+	if s.audit != nil {
+		s.audit(record)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// Client is a client of a remote signing server.
+type Client struct {
+	address    string
+	httpClient *http.Client
+}
+
+// ClientOption is a configuration option for the client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests to the server.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the server,
+// for instance one returned by ClientTLSConfig.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.httpClient = &http.Client{
+			Transport: &http.Transport{TLSClientConfig: config},
+		}
+	}
+}
+
+// ClientTLSConfig returns a TLS configuration authenticating the client with the given
+// certificate, and verifying the server certificate with the given root CAs.
+func ClientTLSConfig(certificate tls.Certificate, rootCAs *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// NewClient creates a new client for the remote signing server at the given address.
+func NewClient(address string, opts ...ClientOption) (*Client, error) {
+	if address == "" {
+		return nil, fmt.Errorf("remote: missing server address")
+	}
+
+	client := &Client{
+		address:    strings.TrimRight(address, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, apply := range opts {
+		apply(client)
+	}
+
+	return client, nil
+}
+
+// GetPublicKey fetches the public key of a remote signing key, with the hash algorithm
+// the key signs with.
+func (c *Client) GetPublicKey(ctx context.Context, keyID string) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+	request := PublicKeyRequest{
+		Version: ProtocolVersion,
+		KeyID:   keyID,
+	}
+
+	var result PublicKeyResponse
+	err := c.do(ctx, publicKeyPath, request, &result)
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("remote: failed to fetch public key: %w", err)
+	}
+
+	sigAlgo := crypto.StringToSignatureAlgorithm(result.SignatureAlgorithm)
+	hashAlgo := crypto.StringToHashAlgorithm(result.HashAlgorithm)
+	if !crypto.CompatibleAlgorithms(sigAlgo, hashAlgo) {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf(
+				"remote: unsupported signature algorithm %s and hash algorithm %s",
+				result.SignatureAlgorithm,
+				result.HashAlgorithm,
+			)
+	}
+
+	publicKey, err := crypto.DecodePublicKeyHex(sigAlgo, result.PublicKey)
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("remote: failed to decode public key: %w", err)
+	}
+
+	return publicKey, hashAlgo, nil
+}
+
+// do sends a request to the server and decodes the JSON response into result.
+func (c *Client) do(ctx context.Context, path string, body any, result any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.address+path, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var errRes ErrorResponse
+		_ = json.NewDecoder(res.Body).Decode(&errRes)
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, errRes.Error)
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
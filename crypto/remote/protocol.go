@@ -0,0 +1,109 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package remote provides a remote signing protocol, with a crypto.Signer client
+// and an embeddable server exposing existing signers.
+//
+// The protocol is a versioned JSON over HTTP protocol with two endpoints:
+//   - POST /v1/public-key returns the public key and hash algorithm of a signing key.
+//   - POST /v1/sign signs a message with a signing key.
+//
+// Sign requests carry the message, its hash and the domain of the message, so that the server
+// can audit and check requests before signing. Clients are expected to authenticate
+// with mutual TLS.
+package remote
+
+import (
+	"bytes"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// ProtocolVersion is the version of the remote signing protocol implemented by this package.
+const ProtocolVersion = 1
+
+const (
+	publicKeyPath = "/v1/public-key"
+	signPath      = "/v1/sign"
+)
+
+// Domain is the domain of a signed message, identified by the domain tag prefixing the message.
+type Domain string
+
+const (
+	// DomainTransaction is the domain of transaction payloads and envelopes.
+	DomainTransaction Domain = "transaction"
+	// DomainUser is the domain of user messages.
+	DomainUser Domain = "user"
+	// DomainUnknown is the domain of messages without a known domain tag.
+	DomainUnknown Domain = "unknown"
+)
+
+// DetectDomain returns the domain of a message from its domain tag.
+func DetectDomain(message []byte) Domain {
+	switch {
+	case bytes.HasPrefix(message, flow.TransactionDomainTag[:]):
+		return DomainTransaction
+	case bytes.HasPrefix(message, flow.UserDomainTag[:]):
+		return DomainUser
+	default:
+		return DomainUnknown
+	}
+}
+
+// PublicKeyRequest is a request for the public key of a signing key.
+type PublicKeyRequest struct {
+	Version int    `json:"version"`
+	KeyID   string `json:"keyId"`
+}
+
+// PublicKeyResponse is the public key of a signing key, with the hash algorithm used when signing.
+type PublicKeyResponse struct {
+	Version            int    `json:"version"`
+	KeyID              string `json:"keyId"`
+	SignatureAlgorithm string `json:"signatureAlgorithm"`
+	HashAlgorithm      string `json:"hashAlgorithm"`
+	// PublicKey is the hex encoded public key.
+	PublicKey string `json:"publicKey"`
+}
+
+// SignRequest is a request to sign a message with a signing key.
+type SignRequest struct {
+	Version int    `json:"version"`
+	KeyID   string `json:"keyId"`
+	Domain  Domain `json:"domain"`
+	// Message is the message to sign, including its domain tag.
+	Message []byte `json:"message"`
+	// MessageHash is the hash of the message with the hash algorithm of the signing key.
+	MessageHash []byte `json:"messageHash"`
+	// Metadata is free-form information attached to the request by the client, for audit
+	// and policy checks.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// SignResponse is the signature of a message.
+type SignResponse struct {
+	Version   int    `json:"version"`
+	Signature []byte `json:"signature"`
+}
+
+// ErrorResponse is the body of error responses.
+type ErrorResponse struct {
+	Version int    `json:"version"`
+	Error   string `json:"error"`
+}
@@ -0,0 +1,230 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/remote"
+)
+
+// testCA is a certificate authority issuing the server and client certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return testCA{cert: cert, key: key, pool: pool}
+}
+
+func (ca testCA) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func newSigner(t *testing.T, sigAlgo crypto.SignatureAlgorithm) crypto.InMemorySigner {
+	seed := make([]byte, crypto.MinSeedLength)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	sk, err := crypto.GeneratePrivateKey(sigAlgo, seed)
+	require.NoError(t, err)
+	signer, err := crypto.NewInMemorySigner(sk, crypto.SHA3_256)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestRemoteSigner(t *testing.T) {
+	ca := newTestCA(t)
+
+	// only allow user messages from the "wallet" client with a reason
+	policy := func(ctx context.Context, peer remote.Peer, request remote.SignRequest) error {
+		if peer.Certificates[0].Subject.CommonName != "wallet" {
+			return errors.New("unknown client")
+		}
+		if request.Domain == remote.DomainUser && request.Metadata["reason"] == "" {
+			return errors.New("missing reason")
+		}
+		return nil
+	}
+
+	server := remote.NewServer(remote.WithPolicy(policy))
+	signer := newSigner(t, crypto.ECDSA_P256)
+	require.NoError(t, server.AddSigner("service", signer, crypto.SHA3_256))
+	assert.Error(t, server.AddSigner("invalid", signer, crypto.KMAC128))
+
+	ts := httptest.NewUnstartedServer(server)
+	ts.TLS = remote.ServerTLSConfig(ca.issue(t, "server", x509.ExtKeyUsageServerAuth), ca.pool)
+	ts.StartTLS()
+	defer ts.Close()
+
+	newClient := func(t *testing.T, commonName string) *remote.Client {
+		tlsConfig := remote.ClientTLSConfig(ca.issue(t, commonName, x509.ExtKeyUsageClientAuth), ca.pool)
+		client, err := remote.NewClient(ts.URL, remote.WithTLSConfig(tlsConfig))
+		require.NoError(t, err)
+		return client
+	}
+
+	ctx := context.Background()
+	client := newClient(t, "wallet")
+
+	t.Run("Public key", func(t *testing.T) {
+		pk, hashAlgo, err := client.GetPublicKey(ctx, "service")
+		require.NoError(t, err)
+		assert.True(t, signer.PublicKey().Equals(pk))
+		assert.Equal(t, crypto.SHA3_256, hashAlgo)
+
+		_, _, err = client.GetPublicKey(ctx, "unknown")
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("Sign transaction", func(t *testing.T) {
+		remoteSigner, err := client.SignerForKey(ctx, "service")
+		require.NoError(t, err)
+
+		address := flow.HexToAddress("01")
+		tx := flow.NewTransaction().
+			SetScript([]byte(`transaction {}`)).
+			SetProposalKey(address, 0, 0).
+			SetPayer(address)
+		require.NoError(t, tx.SignEnvelope(address, 0, remoteSigner))
+
+		message := append(flow.TransactionDomainTag[:], tx.EnvelopeMessage()...)
+		valid, err := signer.PublicKey().Verify(tx.EnvelopeSignatures[0].Signature, message, crypto.NewSHA3_256())
+		require.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("Sign user message with metadata", func(t *testing.T) {
+		remoteSigner, err := client.SignerForKey(ctx, "service")
+		require.NoError(t, err)
+
+		_, err = flow.SignUserMessage(remoteSigner, []byte("message"))
+		assert.ErrorContains(t, err, "missing reason")
+
+		signCtx := remote.ContextWithMetadata(ctx, map[string]string{"reason": "login"})
+		signature, err := flow.SignUserMessageContext(signCtx, remoteSigner, []byte("message"))
+		require.NoError(t, err)
+
+		message := append(flow.UserDomainTag[:], []byte("message")...)
+		valid, err := signer.PublicKey().Verify(signature, message, crypto.NewSHA3_256())
+		require.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("Rejected client", func(t *testing.T) {
+		remoteSigner, err := newClient(t, "intruder").SignerForKey(ctx, "service")
+		require.NoError(t, err)
+
+		_, err = remoteSigner.Sign(append(flow.TransactionDomainTag[:], 1, 2, 3))
+		assert.ErrorContains(t, err, "unknown client")
+	})
+
+	t.Run("Missing client certificate", func(t *testing.T) {
+		tlsConfig := &tls.Config{RootCAs: ca.pool}
+		client, err := remote.NewClient(ts.URL, remote.WithTLSConfig(tlsConfig))
+		require.NoError(t, err)
+
+		_, _, err = client.GetPublicKey(ctx, "service")
+		assert.Error(t, err)
+	})
+}
+
+func TestServerWithoutClientCertificates(t *testing.T) {
+	signer := newSigner(t, crypto.ECDSA_secp256k1)
+
+	server := remote.NewServer(remote.WithoutClientCertificates())
+	require.NoError(t, server.AddSigner("service", signer, crypto.SHA3_256))
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	client, err := remote.NewClient(ts.URL)
+	require.NoError(t, err)
+
+	remoteSigner, err := client.SignerForKey(context.Background(), "service")
+	require.NoError(t, err)
+
+	signature, err := remoteSigner.Sign([]byte("message"))
+	require.NoError(t, err)
+
+	valid, err := signer.PublicKey().Verify(signature, []byte("message"), crypto.NewSHA3_256())
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// the default server requires client certificates
+	ts = httptest.NewServer(remote.NewServer())
+	defer ts.Close()
+
+	client, err = remote.NewClient(ts.URL)
+	require.NoError(t, err)
+	_, _, err = client.GetPublicKey(context.Background(), "service")
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,257 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// maxRequestSize is the maximum size of a request body accepted by the server.
+const maxRequestSize = 1 << 20
+
+// Peer describes the client of a request.
+type Peer struct {
+	RemoteAddr string
+	// Certificates is the verified certificate chain of the client, the client certificate first.
+	// It is empty if the server doesn't require client certificates.
+	Certificates []*x509.Certificate
+}
+
+// PolicyFunc checks a sign request before it is signed.
+//
+// The request is rejected with a 403 status if the function returns an error,
+// the error message being returned to the client.
+type PolicyFunc func(ctx context.Context, peer Peer, request SignRequest) error
+
+// Server is an HTTP handler serving the remote signing protocol for a set of signers.
+//
+// The server is meant to be embedded in an http.Server configured with ServerTLSConfig,
+// so that clients are authenticated with mutual TLS.
+type Server struct {
+	mu                 sync.RWMutex
+	keys               map[string]serverKey
+	policy             PolicyFunc
+	requireClientCerts bool
+	mux                *http.ServeMux
+}
+
+type serverKey struct {
+	signer   crypto.ContextSigner
+	hashAlgo crypto.HashAlgorithm
+}
+
+// ServerOption is a configuration option for the server.
+type ServerOption func(*Server)
+
+// WithPolicy sets the policy checking sign requests before they are signed.
+func WithPolicy(policy PolicyFunc) ServerOption {
+	return func(s *Server) {
+		s.policy = policy
+	}
+}
+
+// WithoutClientCertificates disables the verification of client certificates by the server,
+// for instance when TLS is terminated by a proxy authenticating the clients.
+func WithoutClientCertificates() ServerOption {
+	return func(s *Server) {
+		s.requireClientCerts = false
+	}
+}
+
+// ServerTLSConfig returns a TLS configuration using the given server certificate and
+// requiring client certificates signed by the given CAs.
+func ServerTLSConfig(certificate tls.Certificate, clientCAs *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// NewServer creates a new remote signing server without signers.
+//
+// By default, the server rejects requests without a verified client certificate.
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		keys:               make(map[string]serverKey),
+		requireClientCerts: true,
+		mux:                http.NewServeMux(),
+	}
+	for _, apply := range opts {
+		apply(s)
+	}
+
+	s.mux.HandleFunc("POST "+publicKeyPath, s.handlePublicKey)
+	s.mux.HandleFunc("POST "+signPath, s.handleSign)
+
+	return s
+}
+
+// AddSigner exposes a signer under the given key ID.
+//
+// The hash algorithm is the algorithm the signer hashes messages with,
+// it is reported to clients and used to check the message hashes of sign requests.
+// Requests are served concurrently, the signer must be safe for concurrent use.
+func (s *Server) AddSigner(keyID string, signer crypto.Signer, hashAlgo crypto.HashAlgorithm) error {
+	if keyID == "" {
+		return fmt.Errorf("remote: missing key ID")
+	}
+	sigAlgo := signer.PublicKey().Algorithm()
+	if !crypto.CompatibleAlgorithms(sigAlgo, hashAlgo) {
+		return fmt.Errorf("remote: signature algorithm %s and hashing algorithm %s are incompatible", sigAlgo, hashAlgo)
+	}
+	if _, err := crypto.NewHasher(hashAlgo); err != nil {
+		return fmt.Errorf("remote: unsupported hashing algorithm %s: %w", hashAlgo, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[keyID] = serverKey{
+		signer:   crypto.NewContextSigner(signer),
+		hashAlgo: hashAlgo,
+	}
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.requireClientCerts && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+		writeError(w, http.StatusUnauthorized, "client certificate required")
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handlePublicKey(w http.ResponseWriter, r *http.Request) {
+	var request PublicKeyRequest
+	if !decodeRequest(w, r, &request, &request.Version) {
+		return
+	}
+
+	key, ok := s.key(request.KeyID)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown key %q", request.KeyID))
+		return
+	}
+
+	publicKey := key.signer.PublicKey()
+	writeResponse(w, PublicKeyResponse{
+		Version:            ProtocolVersion,
+		KeyID:              request.KeyID,
+		SignatureAlgorithm: publicKey.Algorithm().String(),
+		HashAlgorithm:      key.hashAlgo.String(),
+		PublicKey:          hex.EncodeToString(publicKey.Encode()),
+	})
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	var request SignRequest
+	if !decodeRequest(w, r, &request, &request.Version) {
+		return
+	}
+
+	key, ok := s.key(request.KeyID)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown key %q", request.KeyID))
+		return
+	}
+
+	// the hash and domain are checked against the message so that policies can rely on them
+	hasher, err := crypto.NewHasher(key.hashAlgo)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !bytes.Equal(hasher.ComputeHash(request.Message), request.MessageHash) {
+		writeError(w, http.StatusBadRequest, "message hash doesn't match the message")
+		return
+	}
+	if DetectDomain(request.Message) != request.Domain {
+		writeError(w, http.StatusBadRequest, "domain doesn't match the message")
+		return
+	}
+
+	if s.policy != nil {
+		peer := Peer{RemoteAddr: r.RemoteAddr}
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			peer.Certificates = r.TLS.VerifiedChains[0]
+		}
+		if err := s.policy(r.Context(), peer, request); err != nil {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+	}
+
+	signature, err := key.signer.SignContext(r.Context(), request.Message)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to sign: %s", err))
+		return
+	}
+
+	writeResponse(w, SignResponse{
+		Version:   ProtocolVersion,
+		Signature: signature,
+	})
+}
+
+func (s *Server) key(keyID string) (serverKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[keyID]
+	return key, ok
+}
+
+// decodeRequest decodes the JSON request body and checks its protocol version.
+// It writes an error response and returns false if the request is invalid.
+func decodeRequest(w http.ResponseWriter, r *http.Request, request any, version *int) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %s", err))
+		return false
+	}
+	if *version != ProtocolVersion {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported protocol version %d", *version))
+		return false
+	}
+	return true
+}
+
+func writeResponse(w http.ResponseWriter, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Version: ProtocolVersion,
+		Error:   message,
+	})
+}
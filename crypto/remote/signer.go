@@ -0,0 +1,112 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+var _ crypto.ContextSigner = (*Signer)(nil)
+
+// Signer is a remote signing server implementation of crypto.Signer and crypto.ContextSigner.
+type Signer struct {
+	ctx    context.Context
+	client *Client
+	keyID  string
+	// public key for easier access
+	publicKey crypto.PublicKey
+	// Hash algorithm the remote key signs with
+	hashAlgo crypto.HashAlgorithm
+}
+
+// SignerForKey returns a new remote signer for a signing key of the server.
+func (c *Client) SignerForKey(
+	ctx context.Context,
+	keyID string,
+) (*Signer, error) {
+	pk, hashAlgo, err := c.GetPublicKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		ctx:       ctx,
+		client:    c,
+		keyID:     keyID,
+		publicKey: pk,
+		hashAlgo:  hashAlgo,
+	}, nil
+}
+
+type metadataKey struct{}
+
+// ContextWithMetadata returns a copy of the context carrying metadata
+// attached to the sign requests sent with the context.
+func ContextWithMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// MetadataFromContext returns the metadata carried by the context, if any.
+func MetadataFromContext(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
+
+// Sign signs the given message using the remote signing key for this signer.
+//
+// The request is bound to the context passed to `SignerForKey`.
+// Use `SignContext` to bind each request to its own context.
+func (s *Signer) Sign(message []byte) ([]byte, error) {
+	return s.SignContext(s.ctx, message)
+}
+
+// SignContext signs the given message using the remote signing key for this signer.
+//
+// The request carries the metadata attached to the context with ContextWithMetadata.
+// The request is cancelled if the context is done before it completes.
+func (s *Signer) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	hasher, err := crypto.NewHasher(s.hashAlgo)
+	if err != nil {
+		return nil, fmt.Errorf("remote: failed to sign: %w", err)
+	}
+
+	request := SignRequest{
+		Version:     ProtocolVersion,
+		KeyID:       s.keyID,
+		Domain:      DetectDomain(message),
+		Message:     message,
+		MessageHash: hasher.ComputeHash(message),
+		Metadata:    MetadataFromContext(ctx),
+	}
+
+	var result SignResponse
+	err = s.client.do(ctx, signPath, request, &result)
+	if err != nil {
+		return nil, fmt.Errorf("remote: failed to sign: %w", err)
+	}
+
+	return result.Signature, nil
+}
+
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.publicKey
+}
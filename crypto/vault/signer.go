@@ -0,0 +1,147 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/internal"
+)
+
+var _ crypto.ContextSigner = (*Signer)(nil)
+
+// Signer is a Vault Transit implementation of crypto.Signer and crypto.ContextSigner.
+type Signer struct {
+	ctx    context.Context
+	client *Client
+	key    Key
+	// ECDSA is the only algorithm supported by this package. The signature algorithm
+	// therefore represents the elliptic curve used. The curve is needed to parse the Vault signature.
+	curve crypto.SignatureAlgorithm
+	// public key for easier access
+	publicKey crypto.PublicKey
+	// Hash algorithm associated to the Vault signing key
+	hashAlgo crypto.HashAlgorithm
+}
+
+// SignerForKey returns a new Vault Transit signer for an asymmetric signing key.
+//
+// Only ECDSA keys on P-256 and secp256k1 curves and SHA2-256 are supported.
+func (c *Client) SignerForKey(
+	ctx context.Context,
+	key Key,
+) (*Signer, error) {
+	pk, hashAlgo, err := c.GetPublicKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		ctx:       ctx,
+		client:    c,
+		key:       key,
+		curve:     pk.Algorithm(),
+		publicKey: pk,
+		hashAlgo:  hashAlgo,
+	}, nil
+}
+
+type signRequest struct {
+	Input               string `json:"input"`
+	KeyVersion          int    `json:"key_version,omitempty"`
+	HashAlgorithm       string `json:"hash_algorithm"`
+	MarshalingAlgorithm string `json:"marshaling_algorithm"`
+}
+
+type signResponse struct {
+	Data struct {
+		Signature string `json:"signature"`
+	} `json:"data"`
+}
+
+// Sign signs the given message using the Vault signing key for this signer.
+//
+// The Vault request is bound to the context passed to `SignerForKey`.
+// Use `SignContext` to bind each request to its own context.
+func (s *Signer) Sign(message []byte) ([]byte, error) {
+	return s.SignContext(s.ctx, message)
+}
+
+// SignContext signs the given message using the Vault signing key for this signer.
+//
+// The message is hashed by Vault, and the request is cancelled if the context is done before it completes.
+//
+// Reference: https://developer.hashicorp.com/vault/api-docs/secret/transit#sign-data
+func (s *Signer) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	hashAlgo, err := transitHashAlgorithm(s.hashAlgo)
+	if err != nil {
+		return nil, fmt.Errorf("vault: failed to sign: %w", err)
+	}
+
+	request := signRequest{
+		Input:               base64.StdEncoding.EncodeToString(message),
+		KeyVersion:          s.key.Version,
+		HashAlgorithm:       hashAlgo,
+		MarshalingAlgorithm: "asn1",
+	}
+
+	var result signResponse
+	err = s.client.do(ctx, http.MethodPost, s.key.Mount+"/sign/"+s.key.Name, request, &result)
+	if err != nil {
+		return nil, fmt.Errorf("vault: failed to sign: %w", err)
+	}
+
+	der, err := decodeTransitSignature(result.Data.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("vault: failed to parse signature: %w", err)
+	}
+	sig, err := internal.ParseECDSASignature(der, s.curve)
+	if err != nil {
+		return nil, fmt.Errorf("vault: failed to parse signature: %w", err)
+	}
+	return sig, nil
+}
+
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.publicKey
+}
+
+// decodeTransitSignature decodes a Transit signature of the form "vault:v<version>:<base64 signature>".
+func decodeTransitSignature(signature string) ([]byte, error) {
+	parts := strings.Split(signature, ":")
+	if len(parts) != 3 || parts[0] != "vault" || !strings.HasPrefix(parts[1], "v") {
+		return nil, fmt.Errorf("unexpected signature format %q", signature)
+	}
+	return base64.StdEncoding.DecodeString(parts[2])
+}
+
+// returns the Transit name of the hashing algorithm.
+// This function only covers algorithms supported by the signer. It should be extended
+// whenever a new hashing algorithm needs to be supported (for instance SHA3-256)
+func transitHashAlgorithm(algo crypto.HashAlgorithm) (string, error) {
+	if algo == crypto.SHA2_256 {
+		return "sha2-256", nil
+	}
+	return "", fmt.Errorf("unsupported hash algorithm %s", algo)
+}
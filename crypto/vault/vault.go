@@ -0,0 +1,267 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vault provides a HashiCorp Vault Transit secrets engine
+// implementation of the crypto.Signer interface.
+//
+// The documentation for the Transit engine API can be found here: https://developer.hashicorp.com/vault/api-docs/secret/transit
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+const (
+	keyPathFormat = "%s/keys/%s"
+
+	tokenHeader     = "X-Vault-Token"
+	namespaceHeader = "X-Vault-Namespace"
+)
+
+// Key is a reference to a Vault Transit asymmetric signing key.
+type Key struct {
+	// Mount is the path where the Transit engine is mounted, for instance "transit".
+	Mount string `json:"mount"`
+	// Name is the name of the key in the Transit engine.
+	Name string `json:"name"`
+	// Version is the key version used for signing. The latest version is used if zero.
+	Version int `json:"version"`
+}
+
+// Path returns the Vault path of this key, without the key version.
+func (k Key) Path() string {
+	return fmt.Sprintf(keyPathFormat, k.Mount, k.Name)
+}
+
+// KeyFromPath returns a `Key` from a Vault key path.
+//
+// Example path format: "transit/keys/my-key", optionally followed by a key version: "transit/keys/my-key/2".
+func KeyFromPath(path string) (Key, error) {
+	key := Key{}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	keysIndex := -1
+	for i, part := range parts {
+		if part == "keys" {
+			keysIndex = i
+		}
+	}
+	// the mount path may contain several segments, the key name is a single segment
+	if keysIndex < 1 || keysIndex != len(parts)-2 && keysIndex != len(parts)-3 {
+		return key, fmt.Errorf("vault: wrong format for the key path: %s", path)
+	}
+
+	key.Mount = strings.Join(parts[:keysIndex], "/")
+	key.Name = parts[keysIndex+1]
+	if keysIndex == len(parts)-3 {
+		version, err := strconv.Atoi(parts[keysIndex+2])
+		if err != nil || version < 1 {
+			return key, fmt.Errorf("vault: wrong key version in the key path: %s", path)
+		}
+		key.Version = version
+	}
+
+	return key, nil
+}
+
+// Client is a client for interacting with the Vault Transit API
+// using types native to the Flow Go SDK.
+type Client struct {
+	address    string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+// ClientOption is a configuration option for the client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests to Vault,
+// for instance to configure TLS.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithNamespace sets the Vault Enterprise namespace of the requests.
+func WithNamespace(namespace string) ClientOption {
+	return func(c *Client) {
+		c.namespace = namespace
+	}
+}
+
+// NewClient creates a new Vault client for the server at the given address,
+// authenticated with the given token.
+func NewClient(address string, token string, opts ...ClientOption) (*Client, error) {
+	if address == "" {
+		return nil, fmt.Errorf("vault: missing server address")
+	}
+
+	client := &Client{
+		address:    strings.TrimRight(address, "/"),
+		token:      token,
+		httpClient: http.DefaultClient,
+	}
+	for _, apply := range opts {
+		apply(client)
+	}
+
+	return client, nil
+}
+
+type readKeyResponse struct {
+	Data struct {
+		Type          string `json:"type"`
+		LatestVersion int    `json:"latest_version"`
+		Keys          map[string]struct {
+			PublicKey string `json:"public_key"`
+		} `json:"keys"`
+	} `json:"data"`
+}
+
+// GetPublicKey fetches the public key portion of a Vault Transit asymmetric signing key.
+//
+// Transit keys of the type `ecdsa-p256` and `ecdsa-secp256k1` (where supported by the Vault server)
+// are the only keys supported by the SDK.
+//
+// Ref: https://developer.hashicorp.com/vault/api-docs/secret/transit#read-key
+func (c *Client) GetPublicKey(ctx context.Context, key Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+	var result readKeyResponse
+	err := c.do(ctx, http.MethodGet, key.Path(), nil, &result)
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("vault: failed to fetch public key from Vault API: %w", err)
+	}
+
+	sigAlgo := parseSignatureAlgorithm(result.Data.Type)
+	if sigAlgo == crypto.UnknownSignatureAlgorithm {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf(
+				"vault: unsupported signature algorithm %s",
+				result.Data.Type,
+			)
+	}
+
+	hashAlgo := parseHashAlgorithm(result.Data.Type)
+	if hashAlgo == crypto.UnknownHashAlgorithm {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf(
+				"vault: unsupported hash algorithm %s",
+				result.Data.Type,
+			)
+	}
+
+	version := key.Version
+	if version == 0 {
+		version = result.Data.LatestVersion
+	}
+	keyVersion, ok := result.Data.Keys[strconv.Itoa(version)]
+	if !ok {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("vault: key version %d not found for key %s", version, key.Path())
+	}
+
+	publicKey, err := crypto.DecodePublicKeyPEM(sigAlgo, keyVersion.PublicKey)
+	if err != nil {
+		return nil,
+			crypto.UnknownHashAlgorithm,
+			fmt.Errorf("vault: failed to parse PEM public key: %w", err)
+	}
+
+	return publicKey, hashAlgo, nil
+}
+
+// errorResponse is the body of Vault error responses.
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// do sends a request to the Vault API and decodes the JSON response into result.
+func (c *Client) do(ctx context.Context, method string, path string, body any, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.address+"/v1/"+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(tokenHeader, c.token)
+	if c.namespace != "" {
+		req.Header.Set(namespaceHeader, c.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var errRes errorResponse
+		_ = json.NewDecoder(res.Body).Decode(&errRes)
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.Join(errRes.Errors, "; "))
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// parseSignatureAlgorithm returns the `SignatureAlgorithm` corresponding to the input Transit key type.
+func parseSignatureAlgorithm(keyType string) crypto.SignatureAlgorithm {
+	if keyType == "ecdsa-p256" {
+		return crypto.ECDSA_P256
+	}
+
+	if keyType == "ecdsa-secp256k1" {
+		return crypto.ECDSA_secp256k1
+	}
+
+	return crypto.UnknownSignatureAlgorithm
+}
+
+// parseHashAlgorithm returns the `HashAlgorithm` corresponding to the input Transit key type.
+func parseHashAlgorithm(keyType string) crypto.HashAlgorithm {
+	if keyType == "ecdsa-p256" || keyType == "ecdsa-secp256k1" {
+		return crypto.SHA2_256
+	}
+
+	// the function can be extended to return SHA3-256, which the Transit engine supports.
+	return crypto.UnknownHashAlgorithm
+}
@@ -0,0 +1,218 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vault_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/vault"
+)
+
+func TestKeyFromPath(t *testing.T) {
+	key := vault.Key{
+		Mount: "transit",
+		Name:  "flow-account",
+	}
+
+	assert.Equal(t, "transit/keys/flow-account", key.Path())
+
+	keyFromPath, err := vault.KeyFromPath(key.Path())
+	require.NoError(t, err)
+	assert.Equal(t, key, keyFromPath)
+
+	keyFromPath, err = vault.KeyFromPath("ns/transit/keys/flow-account/3")
+	require.NoError(t, err)
+	assert.Equal(t, vault.Key{Mount: "ns/transit", Name: "flow-account", Version: 3}, keyFromPath)
+
+	for _, path := range []string{"", "transit", "keys/flow-account", "transit/keys", "transit/keys/flow-account/latest"} {
+		_, err = vault.KeyFromPath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+var (
+	oidPublicKeyECDSA      = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveP256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveSECP256K1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// encodePublicKeyPEM encodes a public key in the PKIX PEM form returned by Vault.
+func encodePublicKeyPEM(t *testing.T, pk crypto.PublicKey) string {
+	curve := oidNamedCurveP256
+	if pk.Algorithm() == crypto.ECDSA_secp256k1 {
+		curve = oidNamedCurveSECP256K1
+	}
+	params, err := asn1.Marshal(curve)
+	require.NoError(t, err)
+
+	point := append([]byte{4}, pk.Encode()...)
+	der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// newTransitServer returns a mock Vault Transit server holding a single key.
+func newTransitServer(t *testing.T, keyType string, sk crypto.PrivateKey) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/transit/keys/flow-account", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		res := map[string]any{
+			"data": map[string]any{
+				"type":           keyType,
+				"latest_version": 1,
+				"keys": map[string]any{
+					"1": map[string]any{"public_key": encodePublicKeyPEM(t, sk.PublicKey())},
+				},
+			},
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+
+	mux.HandleFunc("POST /v1/transit/sign/flow-account", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input         string `json:"input"`
+			HashAlgorithm string `json:"hash_algorithm"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "sha2-256", req.HashAlgorithm)
+
+		input, err := base64.StdEncoding.DecodeString(req.Input)
+		require.NoError(t, err)
+
+		// Vault returns ASN.1 DER signatures
+		raw, err := sk.Sign(input, crypto.NewSHA2_256())
+		require.NoError(t, err)
+		der, err := asn1.Marshal(struct{ R, S *big.Int }{
+			R: new(big.Int).SetBytes(raw[:32]),
+			S: new(big.Int).SetBytes(raw[32:]),
+		})
+		require.NoError(t, err)
+
+		res := map[string]any{
+			"data": map[string]any{
+				"signature":   "vault:v1:" + base64.StdEncoding.EncodeToString(der),
+				"key_version": 1,
+			},
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestSigning(t *testing.T) {
+	curves := map[crypto.SignatureAlgorithm]string{
+		crypto.ECDSA_P256:      "ecdsa-p256",
+		crypto.ECDSA_secp256k1: "ecdsa-secp256k1",
+	}
+
+	for sigAlgo, keyType := range curves {
+		t.Run(sigAlgo.String(), func(t *testing.T) {
+			seed := make([]byte, crypto.MinSeedLength)
+			_, err := rand.Read(seed)
+			require.NoError(t, err)
+			sk, err := crypto.GeneratePrivateKey(sigAlgo, seed)
+			require.NoError(t, err)
+
+			server := newTransitServer(t, keyType, sk)
+			defer server.Close()
+
+			ctx := context.Background()
+			client, err := vault.NewClient(server.URL, "test-token")
+			require.NoError(t, err)
+
+			key := vault.Key{Mount: "transit", Name: "flow-account"}
+
+			pk, hashAlgo, err := client.GetPublicKey(ctx, key)
+			require.NoError(t, err)
+			assert.True(t, sk.PublicKey().Equals(pk))
+			assert.Equal(t, crypto.SHA2_256, hashAlgo)
+
+			signer, err := client.SignerForKey(ctx, key)
+			require.NoError(t, err)
+
+			msg := []byte("random_message")
+			sig, err := signer.Sign(msg)
+			require.NoError(t, err)
+
+			valid, err := pk.Verify(sig, msg, crypto.NewSHA2_256())
+			require.NoError(t, err)
+			assert.True(t, valid)
+		})
+	}
+
+	t.Run("Unsupported key type", func(t *testing.T) {
+		seed := make([]byte, crypto.MinSeedLength)
+		sk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, seed)
+		require.NoError(t, err)
+
+		server := newTransitServer(t, "rsa-2048", sk)
+		defer server.Close()
+
+		client, err := vault.NewClient(server.URL, "test-token")
+		require.NoError(t, err)
+
+		_, err = client.SignerForKey(context.Background(), vault.Key{Mount: "transit", Name: "flow-account"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Permission denied", func(t *testing.T) {
+		seed := make([]byte, crypto.MinSeedLength)
+		sk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, seed)
+		require.NoError(t, err)
+
+		server := newTransitServer(t, "ecdsa-p256", sk)
+		defer server.Close()
+
+		client, err := vault.NewClient(server.URL, "wrong-token")
+		require.NoError(t, err)
+
+		_, _, err = client.GetPublicKey(context.Background(), vault.Key{Mount: "transit", Name: "flow-account"})
+		assert.ErrorContains(t, err, "permission denied")
+	})
+}
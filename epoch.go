@@ -0,0 +1,234 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// Role is the role of a node in the Flow network.
+type Role string
+
+const (
+	RoleCollection   Role = "collection"
+	RoleConsensus    Role = "consensus"
+	RoleExecution    Role = "execution"
+	RoleVerification Role = "verification"
+	RoleAccess       Role = "access"
+)
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// Identity is a node participating in an epoch, as registered by the staking contract.
+type Identity struct {
+	NodeID        Identifier
+	Address       string
+	Role          Role
+	InitialWeight uint64
+	// StakingKey is the BLS key used by the node to sign votes, approvals and receipts.
+	StakingKey crypto.PublicKey
+	// NetworkKey is the ECDSA P-256 key used by the node to secure its network connections.
+	NetworkKey crypto.PublicKey
+}
+
+// encodableIdentity is the JSON representation of an identity used by the protocol,
+// with base64-encoded public keys.
+type encodableIdentity struct {
+	NodeID        Identifier
+	Address       string
+	Role          Role
+	InitialWeight uint64
+	StakingPubKey []byte
+	NetworkPubKey []byte
+}
+
+func (i Identity) MarshalJSON() ([]byte, error) {
+	enc := encodableIdentity{
+		NodeID:        i.NodeID,
+		Address:       i.Address,
+		Role:          i.Role,
+		InitialWeight: i.InitialWeight,
+	}
+	if i.StakingKey != nil {
+		enc.StakingPubKey = i.StakingKey.Encode()
+	}
+	if i.NetworkKey != nil {
+		enc.NetworkPubKey = i.NetworkKey.Encode()
+	}
+	return json.Marshal(enc)
+}
+
+func (i *Identity) UnmarshalJSON(data []byte) error {
+	var enc encodableIdentity
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+
+	*i = Identity{
+		NodeID:        enc.NodeID,
+		Address:       enc.Address,
+		Role:          enc.Role,
+		InitialWeight: enc.InitialWeight,
+	}
+
+	var err error
+	if len(enc.StakingPubKey) > 0 {
+		i.StakingKey, err = crypto.DecodePublicKey(crypto.BLS_BLS12_381, enc.StakingPubKey)
+		if err != nil {
+			return fmt.Errorf("invalid staking key of node %s: %w", enc.NodeID, err)
+		}
+	}
+	if len(enc.NetworkPubKey) > 0 {
+		i.NetworkKey, err = crypto.DecodePublicKey(crypto.ECDSA_P256, enc.NetworkPubKey)
+		if err != nil {
+			return fmt.Errorf("invalid network key of node %s: %w", enc.NodeID, err)
+		}
+	}
+	return nil
+}
+
+// IdentityList is a list of node identities.
+type IdentityList []*Identity
+
+// ByNodeID returns the identity of the node with the given ID, if it is in the list.
+func (l IdentityList) ByNodeID(nodeID Identifier) (*Identity, bool) {
+	for _, identity := range l {
+		if identity.NodeID == nodeID {
+			return identity, true
+		}
+	}
+	return nil, false
+}
+
+// Filter returns the identities of the list for which the filter returns true.
+func (l IdentityList) Filter(filter func(*Identity) bool) IdentityList {
+	var filtered IdentityList
+	for _, identity := range l {
+		if filter(identity) {
+			filtered = append(filtered, identity)
+		}
+	}
+	return filtered
+}
+
+// TotalWeight returns the sum of the initial weights of the identities.
+func (l IdentityList) TotalWeight() uint64 {
+	var total uint64
+	for _, identity := range l {
+		total += identity.InitialWeight
+	}
+	return total
+}
+
+// EpochSetup is the service event emitted when the participants of the next epoch are determined.
+type EpochSetup struct {
+	// Counter is the counter of the epoch being set up.
+	Counter            uint64
+	FirstView          uint64
+	DKGPhase1FinalView uint64
+	DKGPhase2FinalView uint64
+	DKGPhase3FinalView uint64
+	FinalView          uint64
+	// Participants are the nodes participating in the epoch, in canonical order.
+	Participants IdentityList
+	// Assignments are the node IDs of each collector cluster of the epoch.
+	Assignments    [][]Identifier
+	RandomSource   []byte
+	TargetDuration uint64
+	TargetEndTime  uint64
+}
+
+// ClusterQCVoteData is the root quorum certificate of a collector cluster, with the IDs of its voters.
+type ClusterQCVoteData struct {
+	SigData  []byte
+	VoterIDs []Identifier
+}
+
+// EpochCommit is the service event emitted when the setup of the next epoch is completed,
+// with the results of the distributed key generation of the random beacon.
+type EpochCommit struct {
+	// Counter is the counter of the epoch being committed.
+	Counter uint64
+	// ClusterQCs are the root quorum certificates of the clusters, in the order of the setup assignments.
+	ClusterQCs []ClusterQCVoteData
+	// DKGGroupKey is the group key of the random beacon.
+	DKGGroupKey crypto.PublicKey
+	// DKGParticipantKeys are the key shares of the random beacon participants, by beacon index.
+	DKGParticipantKeys []crypto.PublicKey
+	// DKGIndexMap maps the IDs of the random beacon participants to their beacon index.
+	DKGIndexMap map[Identifier]int
+}
+
+// encodableEpochCommit is the JSON representation of an epoch commit used by the protocol,
+// with hex-encoded public keys.
+type encodableEpochCommit struct {
+	Counter            uint64
+	ClusterQCs         []ClusterQCVoteData
+	DKGGroupKey        string
+	DKGParticipantKeys []string
+	DKGIndexMap        map[Identifier]int
+}
+
+func (c EpochCommit) MarshalJSON() ([]byte, error) {
+	enc := encodableEpochCommit{
+		Counter:     c.Counter,
+		ClusterQCs:  c.ClusterQCs,
+		DKGIndexMap: c.DKGIndexMap,
+	}
+	if c.DKGGroupKey != nil {
+		enc.DKGGroupKey = hex.EncodeToString(c.DKGGroupKey.Encode())
+	}
+	for _, key := range c.DKGParticipantKeys {
+		enc.DKGParticipantKeys = append(enc.DKGParticipantKeys, hex.EncodeToString(key.Encode()))
+	}
+	return json.Marshal(enc)
+}
+
+func (c *EpochCommit) UnmarshalJSON(data []byte) error {
+	var enc encodableEpochCommit
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+
+	*c = EpochCommit{
+		Counter:     enc.Counter,
+		ClusterQCs:  enc.ClusterQCs,
+		DKGIndexMap: enc.DKGIndexMap,
+	}
+
+	var err error
+	c.DKGGroupKey, err = crypto.DecodePublicKeyHex(crypto.BLS_BLS12_381, enc.DKGGroupKey)
+	if err != nil {
+		return fmt.Errorf("invalid DKG group key: %w", err)
+	}
+	for i, encoded := range enc.DKGParticipantKeys {
+		key, err := crypto.DecodePublicKeyHex(crypto.BLS_BLS12_381, encoded)
+		if err != nil {
+			return fmt.Errorf("invalid DKG key of participant %d: %w", i, err)
+		}
+		c.DKGParticipantKeys = append(c.DKGParticipantKeys, key)
+	}
+	return nil
+}
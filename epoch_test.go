@@ -0,0 +1,96 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+func testKey(t *testing.T, sigAlgo crypto.SignatureAlgorithm) crypto.PublicKey {
+	seed := make([]byte, crypto.MinSeedLength)
+	key, err := crypto.GeneratePrivateKey(sigAlgo, seed)
+	require.NoError(t, err)
+	return key.PublicKey()
+}
+
+func TestIdentifier_MarshalText(t *testing.T) {
+	id := testID(1)
+
+	data, err := json.Marshal(map[flow.Identifier]flow.Identifier{id: id})
+	require.NoError(t, err)
+	assert.Equal(t, `{"`+id.Hex()+`":"`+id.Hex()+`"}`, string(data))
+
+	var decoded map[flow.Identifier]flow.Identifier
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, id, decoded[id])
+
+	var invalid flow.Identifier
+	assert.Error(t, invalid.UnmarshalText([]byte("0102")))
+	assert.Error(t, invalid.UnmarshalText([]byte("zz")))
+}
+
+func TestIdentity_JSON(t *testing.T) {
+	identity := flow.Identity{
+		NodeID:        testID(1),
+		Address:       "consensus.flow:3569",
+		Role:          flow.RoleConsensus,
+		InitialWeight: 100,
+		StakingKey:    testKey(t, crypto.BLS_BLS12_381),
+		NetworkKey:    testKey(t, crypto.ECDSA_P256),
+	}
+
+	data, err := json.Marshal(identity)
+	require.NoError(t, err)
+
+	var decoded flow.Identity
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, identity.NodeID, decoded.NodeID)
+	assert.Equal(t, identity.Role, decoded.Role)
+	assert.True(t, identity.StakingKey.Equals(decoded.StakingKey))
+	assert.True(t, identity.NetworkKey.Equals(decoded.NetworkKey))
+}
+
+func TestEpochCommit_JSON(t *testing.T) {
+	commit := flow.EpochCommit{
+		Counter: 2,
+		ClusterQCs: []flow.ClusterQCVoteData{
+			{SigData: []byte{1, 2}, VoterIDs: []flow.Identifier{testID(1)}},
+		},
+		DKGGroupKey:        testKey(t, crypto.BLS_BLS12_381),
+		DKGParticipantKeys: []crypto.PublicKey{testKey(t, crypto.BLS_BLS12_381)},
+		DKGIndexMap:        map[flow.Identifier]int{testID(1): 0},
+	}
+
+	data, err := json.Marshal(commit)
+	require.NoError(t, err)
+
+	var decoded flow.EpochCommit
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, commit.ClusterQCs, decoded.ClusterQCs)
+	assert.Equal(t, commit.DKGIndexMap, decoded.DKGIndexMap)
+	assert.True(t, commit.DKGGroupKey.Equals(decoded.DKGGroupKey))
+	require.Len(t, decoded.DKGParticipantKeys, 1)
+}
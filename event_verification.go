@@ -0,0 +1,143 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/ccf"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// ChunkEventsMismatch is a chunk whose events hash doesn't match the hash of the verified events.
+type ChunkEventsMismatch struct {
+	// ChunkIndex is the index of the chunk in the execution result.
+	ChunkIndex int
+	// Expected is the events hash committed to by the chunk.
+	Expected crypto.Hash
+	// Computed is the hash of the verified events of the chunk.
+	Computed crypto.Hash
+	// EventCount is the number of verified events of the chunk.
+	EventCount int
+}
+
+// EventsVerificationResult is the result of the verification of the events of a block.
+type EventsVerificationResult struct {
+	// Mismatched lists the chunks whose events hash doesn't match the verified events.
+	Mismatched []ChunkEventsMismatch
+}
+
+// Valid returns true if the events of all the chunks match their events hash.
+func (r EventsVerificationResult) Valid() bool {
+	return len(r.Mismatched) == 0
+}
+
+// VerifyBlockEvents checks the events emitted by the transactions of a block against the
+// events hashes of the chunks of its execution result.
+//
+// The events must be all the events of the block, as returned with the transaction results
+// of the block. Events are grouped by chunk using the number of transactions of each chunk,
+// the chunk of a collection containing the events of its transactions, and the last chunk
+// the events of the system transactions.
+//
+// Events hashes are computed over CCF-encoded payloads: JSON-CDC payloads are re-encoded
+// in CCF before hashing. Re-encoding relies on the types of the decoded values, so events
+// with fields of abstract types, like AnyStruct, may not be re-encoded exactly. In that case,
+// fetch the events in the CCF encoding.
+func VerifyBlockEvents(result *ExecutionResult, events []Event) (EventsVerificationResult, error) {
+	// the first transaction index of each chunk, and the end of the last chunk
+	boundaries := make([]int, len(result.Chunks)+1)
+	for i, chunk := range result.Chunks {
+		boundaries[i+1] = boundaries[i] + int(chunk.NumberOfTransactions)
+	}
+
+	chunkEvents := make([][]Event, len(result.Chunks))
+	for _, event := range events {
+		index := sort.SearchInts(boundaries, event.TransactionIndex+1) - 1
+		if event.TransactionIndex < 0 || index >= len(result.Chunks) {
+			return EventsVerificationResult{}, fmt.Errorf(
+				"event %s of transaction %d is outside of the %d transactions of the execution result",
+				event.Type, event.TransactionIndex, boundaries[len(result.Chunks)],
+			)
+		}
+
+		payload, err := ccfEventPayload(event)
+		if err != nil {
+			return EventsVerificationResult{}, err
+		}
+		event.Payload = payload
+		chunkEvents[index] = append(chunkEvents[index], event)
+	}
+
+	var verification EventsVerificationResult
+	for i, chunk := range result.Chunks {
+		es := chunkEvents[i]
+		sort.SliceStable(es, func(i, j int) bool {
+			if es[i].TransactionIndex != es[j].TransactionIndex {
+				return es[i].TransactionIndex < es[j].TransactionIndex
+			}
+			return es[i].EventIndex < es[j].EventIndex
+		})
+
+		hash, err := CalculateEventsHash(es)
+		if err != nil {
+			return EventsVerificationResult{}, err
+		}
+		if !hash.Equal(chunk.EventCollection) {
+			verification.Mismatched = append(verification.Mismatched, ChunkEventsMismatch{
+				ChunkIndex: i,
+				Expected:   chunk.EventCollection,
+				Computed:   hash,
+				EventCount: len(es),
+			})
+		}
+	}
+
+	return verification, nil
+}
+
+// ccfEventPayload returns the CCF encoding of the event payload, re-encoding JSON-CDC payloads.
+func ccfEventPayload(event Event) ([]byte, error) {
+	if ccf.HasMsgPrefix(event.Payload) {
+		return event.Payload, nil
+	}
+
+	value := event.Value
+	if value.EventType == nil {
+		decoded, err := jsoncdc.Decode(nil, event.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode payload of event %s: %w", event.Type, err)
+		}
+		var ok bool
+		value, ok = decoded.(cadence.Event)
+		if !ok {
+			return nil, fmt.Errorf("payload of event %s is not an event", event.Type)
+		}
+	}
+
+	payload, err := ccf.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload of event %s in CCF: %w", event.Type, err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,131 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/encoding/ccf"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+var testDepositedType = cadence.NewEventType(
+	common.StringLocation("test"),
+	"Deposited",
+	[]cadence.Field{
+		{Identifier: "amount", Type: cadence.UFix64Type},
+		{Identifier: "to", Type: cadence.AddressType},
+	},
+	nil,
+)
+
+func newTestEvent(t *testing.T, txIndex, eventIndex int, amount uint64) flow.Event {
+	value := cadence.NewEvent([]cadence.Value{
+		cadence.UFix64(amount),
+		cadence.NewAddress(flow.HexToAddress("01")),
+	}).WithType(testDepositedType)
+
+	payload, err := ccf.Encode(value)
+	require.NoError(t, err)
+
+	return flow.Event{
+		Type:             testDepositedType.ID(),
+		TransactionID:    testID(byte(txIndex)),
+		TransactionIndex: txIndex,
+		EventIndex:       eventIndex,
+		Value:            value,
+		Payload:          payload,
+	}
+}
+
+func TestVerifyBlockEvents(t *testing.T) {
+	events := []flow.Event{
+		newTestEvent(t, 0, 0, 100),
+		newTestEvent(t, 0, 1, 200),
+		newTestEvent(t, 1, 0, 300),
+		newTestEvent(t, 2, 0, 400),
+	}
+
+	collectionHash, err := flow.CalculateEventsHash(events[:3])
+	require.NoError(t, err)
+	systemHash, err := flow.CalculateEventsHash(events[3:])
+	require.NoError(t, err)
+
+	result := &flow.ExecutionResult{
+		Chunks: []*flow.Chunk{
+			{Index: 0, NumberOfTransactions: 2, EventCollection: collectionHash},
+			{Index: 1, NumberOfTransactions: 1, EventCollection: systemHash},
+		},
+	}
+
+	t.Run("CCF events", func(t *testing.T) {
+		// events are ordered before hashing
+		shuffled := []flow.Event{events[3], events[1], events[2], events[0]}
+
+		verification, err := flow.VerifyBlockEvents(result, shuffled)
+		require.NoError(t, err)
+		assert.True(t, verification.Valid())
+	})
+
+	t.Run("JSON-CDC events", func(t *testing.T) {
+		jsonEvents := make([]flow.Event, len(events))
+		for i, event := range events {
+			payload, err := jsoncdc.Encode(event.Value)
+			require.NoError(t, err)
+			event.Payload = payload
+			event.Value = cadence.Event{}
+			jsonEvents[i] = event
+		}
+
+		verification, err := flow.VerifyBlockEvents(result, jsonEvents)
+		require.NoError(t, err)
+		assert.True(t, verification.Valid())
+	})
+
+	t.Run("Modified event", func(t *testing.T) {
+		modified := append([]flow.Event{}, events...)
+		modified[2] = newTestEvent(t, 1, 0, 301)
+
+		verification, err := flow.VerifyBlockEvents(result, modified)
+		require.NoError(t, err)
+		assert.False(t, verification.Valid())
+		require.Len(t, verification.Mismatched, 1)
+		assert.Equal(t, 0, verification.Mismatched[0].ChunkIndex)
+		assert.Equal(t, collectionHash, verification.Mismatched[0].Expected)
+		assert.Equal(t, 3, verification.Mismatched[0].EventCount)
+	})
+
+	t.Run("Missing event", func(t *testing.T) {
+		verification, err := flow.VerifyBlockEvents(result, events[:3])
+		require.NoError(t, err)
+		require.Len(t, verification.Mismatched, 1)
+		assert.Equal(t, 1, verification.Mismatched[0].ChunkIndex)
+	})
+
+	t.Run("Event outside of the result", func(t *testing.T) {
+		_, err := flow.VerifyBlockEvents(result, append(events, newTestEvent(t, 3, 0, 500)))
+		assert.Error(t, err)
+	})
+}
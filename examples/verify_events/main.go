@@ -22,9 +22,8 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/onflow/flow-go-sdk/access/http"
-
 	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/access/grpc"
 
 	"github.com/onflow/flow-go-sdk/examples"
 )
@@ -37,51 +36,33 @@ func main() {
 // In mature Flow we provision different methods to verify the subset of events queried, however
 // for now the only verification available is the hash of all the events emitted in a Chunk/Collection.
 // This hash is verified by Verification Nodes so it's correctness is checked.
-// For users, its possible to get all the events for chunk, calculate and compare the resulting hash
+// For users, its possible to get all the events of a block and compare their hash with the hash of each chunk.
 func VerifyEventsDemo() {
 	ctx := context.Background()
-	flowClient, err := http.NewClient(http.TestnetHost)
+	flowClient, err := grpc.NewClient(grpc.TestnetHost)
 	examples.Handle(err)
 
 	latestBlockHeader, err := flowClient.GetLatestBlockHeader(ctx, true)
 	examples.Handle(err)
 
-	block, err := flowClient.GetBlockByID(ctx, latestBlockHeader.ID)
+	executionResult, err := flowClient.GetExecutionResultForBlockID(ctx, latestBlockHeader.ID)
 	examples.Handle(err)
 
-	executionResult, err := flowClient.GetExecutionResultForBlockID(ctx, block.ID)
+	// the results of all the transactions of the block, including the system transactions
+	transactionResults, err := flowClient.GetTransactionResultsByBlockID(ctx, latestBlockHeader.ID)
 	examples.Handle(err)
 
-	totalEvents := 0
-
-	for i, colGuarantee := range block.CollectionGuarantees {
-		collection, err := flowClient.GetCollection(ctx, colGuarantee.CollectionID)
-		examples.Handle(err)
-
-		events := make([]flow.Event, 0)
-
-		// only way to get all the events regardless of the type is querying by transaction
-		for _, txID := range collection.TransactionIDs {
-			transactionResult, err := flowClient.GetTransactionResult(ctx, txID)
-			examples.Handle(err)
-
-			for _, event := range transactionResult.Events {
-				events = append(events, event)
-			}
-		}
-
-		calculatedEventsHash, err := flow.CalculateEventsHash(events)
-		examples.Handle(err)
-
-		// there should be more chunks (one last is system chunk), so we don't check range here
-		chunk := executionResult.Chunks[i]
+	events := make([]flow.Event, 0)
+	for _, transactionResult := range transactionResults {
+		events = append(events, transactionResult.Events...)
+	}
 
-		if !calculatedEventsHash.Equal(chunk.EventCollection) {
-			examples.Handle(fmt.Errorf("events hash mismatch, expected %s, calculated %s", chunk.EventCollection, calculatedEventsHash))
-		}
+	verification, err := flow.VerifyBlockEvents(executionResult, events)
+	examples.Handle(err)
 
-		totalEvents += len(events)
+	for _, mismatch := range verification.Mismatched {
+		examples.Handle(fmt.Errorf("events hash mismatch in chunk %d, expected %s, calculated %s", mismatch.ChunkIndex, mismatch.Expected, mismatch.Computed))
 	}
 
-	fmt.Printf("Events verified for block %s, total %d events\n", block.ID, totalEvents)
+	fmt.Printf("Events verified for block %s, total %d events\n", latestBlockHeader.ID, len(events))
 }
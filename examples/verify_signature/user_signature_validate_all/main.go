@@ -160,4 +160,22 @@ func UserSignatureValidateAll() {
 	} else {
 		fmt.Println("Signature verification failed")
 	}
+
+	// the signatures can also be verified offline, with the account keys
+	valid, err := flow.VerifyUserSignatures(
+		message,
+		[]flow.CompositeSignature{
+			{Address: account.Address, KeyIndex: 1, Signature: signatureBob},
+			{Address: account.Address, KeyIndex: 0, Signature: signatureAlice},
+		},
+		account.Keys,
+		flow.ValidateAll,
+	)
+	examples.Handle(err)
+
+	if valid {
+		fmt.Println("Offline signature verification succeeded")
+	} else {
+		fmt.Println("Offline signature verification failed")
+	}
 }
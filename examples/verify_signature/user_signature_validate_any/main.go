@@ -137,4 +137,18 @@ func UserSignatureValidateAny() {
 	} else {
 		fmt.Println("Signature verification failed")
 	}
+
+	// the signature can also be verified offline, with the account keys
+	valid, err := flow.VerifyUserSignature(
+		message,
+		flow.CompositeSignature{Address: account.Address, KeyIndex: 0, Signature: signatureAlice},
+		account.Keys,
+	)
+	examples.Handle(err)
+
+	if valid {
+		fmt.Println("Offline signature verification succeeded")
+	} else {
+		fmt.Println("Offline signature verification failed")
+	}
 }
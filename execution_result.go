@@ -18,13 +18,20 @@
 
 package flow
 
-import "github.com/onflow/flow-go-sdk/crypto"
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
 
 type ExecutionResult struct {
 	PreviousResultID Identifier // commit of the previous ER
 	BlockID          Identifier // commit of the current block
 	Chunks           []*Chunk
 	ServiceEvents    []*ServiceEvent
+	ExecutionDataID  Identifier // commit of the execution data of the block
 }
 
 type Chunk struct {
@@ -36,9 +43,247 @@ type Chunk struct {
 	NumberOfTransactions uint16          // number of transactions inside the collection
 	Index                uint64          // chunk index inside the ER (starts from zero)
 	EndState             StateCommitment // EndState inferred from next chunk or from the ER
+	ServiceEventCount    uint16          // number of service events emitted by the chunk
 }
 
 type ServiceEvent struct {
 	Type    string
 	Payload []byte
 }
+
+// ID returns the ID of the execution result, as referenced by execution receipts and seals.
+//
+// The ID commits to the service events of the result, which are decoded to compute it: see
+// ServiceEvent.ID for the supported service events.
+func (r ExecutionResult) ID() (Identifier, error) {
+	chunks := make([]chunkCanonicalForm, len(r.Chunks))
+	for i, chunk := range r.Chunks {
+		chunks[i] = chunk.canonicalForm()
+	}
+
+	events := make([]serviceEventCanonicalForm, len(r.ServiceEvents))
+	for i, event := range r.ServiceEvents {
+		form, err := event.canonicalForm()
+		if err != nil {
+			return EmptyID, fmt.Errorf("service event %d: %w", i, err)
+		}
+		events[i] = form
+	}
+
+	temp := struct {
+		PreviousResultID Identifier
+		BlockID          Identifier
+		Chunks           []chunkCanonicalForm
+		ServiceEvents    []serviceEventCanonicalForm
+		ExecutionDataID  Identifier
+	}{
+		PreviousResultID: r.PreviousResultID,
+		BlockID:          r.BlockID,
+		Chunks:           chunks,
+		ServiceEvents:    events,
+		ExecutionDataID:  r.ExecutionDataID,
+	}
+	return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&temp))), nil
+}
+
+type chunkBodyCanonicalForm struct {
+	CollectionIndex      uint
+	StartState           StateCommitment
+	EventCollection      Identifier
+	ServiceEventCount    uint16
+	BlockID              Identifier
+	TotalComputationUsed uint64
+	NumberOfTransactions uint64
+}
+
+type chunkCanonicalForm struct {
+	Body     chunkBodyCanonicalForm
+	Index    uint64
+	EndState StateCommitment
+}
+
+func (c Chunk) canonicalForm() chunkCanonicalForm {
+	return chunkCanonicalForm{
+		Body: chunkBodyCanonicalForm{
+			CollectionIndex:      c.CollectionIndex,
+			StartState:           c.StartState,
+			EventCollection:      BytesToID(c.EventCollection),
+			ServiceEventCount:    c.ServiceEventCount,
+			BlockID:              c.BlockID,
+			TotalComputationUsed: c.TotalComputationUsed,
+			NumberOfTransactions: uint64(c.NumberOfTransactions),
+		},
+		Index:    c.Index,
+		EndState: c.EndState,
+	}
+}
+
+// ID returns the ID of the chunk.
+func (c Chunk) ID() Identifier {
+	temp := c.canonicalForm()
+	return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&temp)))
+}
+
+type serviceEventCanonicalForm struct {
+	Type  string
+	Event rlp.RawValue
+}
+
+// ID returns the ID of the service event.
+//
+// The ID is computed from the decoded event, so the service event must be of a type supported
+// by ServiceEvent.Decode, in any of the supported encodings.
+func (s ServiceEvent) ID() (Identifier, error) {
+	temp, err := s.canonicalForm()
+	if err != nil {
+		return EmptyID, err
+	}
+	return HashToID(defaultEntityHasher.ComputeHash(mustRLPEncode(&temp))), nil
+}
+
+func (s ServiceEvent) canonicalForm() (serviceEventCanonicalForm, error) {
+	decoded, err := s.Decode()
+	if err != nil {
+		return serviceEventCanonicalForm{}, err
+	}
+
+	var event interface{}
+	switch e := decoded.(type) {
+	case *EpochSetup:
+		event, err = newEpochSetupCanonicalForm(e)
+	case *EpochCommit:
+		event, err = newEpochCommitCanonicalForm(e)
+	case *EpochRecover:
+		event, err = newEpochRecoverCanonicalForm(e)
+	default:
+		// the other service events are encoded field by field
+		event = decoded
+	}
+	if err != nil {
+		return serviceEventCanonicalForm{}, fmt.Errorf("failed to encode %s service event: %w", s.Type, err)
+	}
+
+	encoded, err := rlpEncode(event)
+	if err != nil {
+		return serviceEventCanonicalForm{}, fmt.Errorf("failed to encode %s service event: %w", s.Type, err)
+	}
+
+	eventType, _ := serviceEventType(s.Type)
+	return serviceEventCanonicalForm{Type: eventType, Event: encoded}, nil
+}
+
+// roleCodes are the codes of the node roles in the canonical encoding of identities.
+var roleCodes = map[Role]uint8{
+	RoleCollection:   1,
+	RoleConsensus:    2,
+	RoleExecution:    3,
+	RoleVerification: 4,
+	RoleAccess:       5,
+}
+
+type identityCanonicalForm struct {
+	NodeID        Identifier
+	Address       string
+	Role          uint8
+	InitialWeight uint64
+	StakingPubKey []byte
+	NetworkPubKey []byte
+}
+
+type epochSetupCanonicalForm struct {
+	Counter            uint64
+	FirstView          uint64
+	DKGPhase1FinalView uint64
+	DKGPhase2FinalView uint64
+	DKGPhase3FinalView uint64
+	FinalView          uint64
+	Participants       []identityCanonicalForm
+	Assignments        [][]Identifier
+	RandomSource       []byte
+	TargetDuration     uint64
+	TargetEndTime      uint64
+}
+
+func newEpochSetupCanonicalForm(setup *EpochSetup) (epochSetupCanonicalForm, error) {
+	participants := make([]identityCanonicalForm, len(setup.Participants))
+	for i, identity := range setup.Participants {
+		role, ok := roleCodes[identity.Role]
+		if !ok {
+			return epochSetupCanonicalForm{}, fmt.Errorf("participant %s has unknown role %q", identity.NodeID, identity.Role)
+		}
+		participants[i] = identityCanonicalForm{
+			NodeID:        identity.NodeID,
+			Address:       identity.Address,
+			Role:          role,
+			InitialWeight: identity.InitialWeight,
+		}
+		if identity.StakingKey != nil {
+			participants[i].StakingPubKey = identity.StakingKey.Encode()
+		}
+		if identity.NetworkKey != nil {
+			participants[i].NetworkPubKey = identity.NetworkKey.Encode()
+		}
+	}
+
+	return epochSetupCanonicalForm{
+		Counter:            setup.Counter,
+		FirstView:          setup.FirstView,
+		DKGPhase1FinalView: setup.DKGPhase1FinalView,
+		DKGPhase2FinalView: setup.DKGPhase2FinalView,
+		DKGPhase3FinalView: setup.DKGPhase3FinalView,
+		FinalView:          setup.FinalView,
+		Participants:       participants,
+		Assignments:        setup.Assignments,
+		RandomSource:       setup.RandomSource,
+		TargetDuration:     setup.TargetDuration,
+		TargetEndTime:      setup.TargetEndTime,
+	}, nil
+}
+
+type epochCommitCanonicalForm struct {
+	Counter            uint64
+	ClusterQCs         []ClusterQCVoteData
+	DKGGroupKey        []byte
+	DKGParticipantKeys [][]byte
+	// DKGIndexMap lists the IDs of the DKG participants by index.
+	DKGIndexMap []Identifier
+}
+
+func newEpochCommitCanonicalForm(commit *EpochCommit) (epochCommitCanonicalForm, error) {
+	form := epochCommitCanonicalForm{
+		Counter:            commit.Counter,
+		ClusterQCs:         commit.ClusterQCs,
+		DKGParticipantKeys: make([][]byte, len(commit.DKGParticipantKeys)),
+		DKGIndexMap:        make([]Identifier, len(commit.DKGIndexMap)),
+	}
+	if commit.DKGGroupKey != nil {
+		form.DKGGroupKey = commit.DKGGroupKey.Encode()
+	}
+	for i, key := range commit.DKGParticipantKeys {
+		form.DKGParticipantKeys[i] = key.Encode()
+	}
+	for nodeID, index := range commit.DKGIndexMap {
+		if index < 0 || index >= len(form.DKGIndexMap) {
+			return epochCommitCanonicalForm{}, fmt.Errorf("DKG participant %s has index %d out of %d participants", nodeID, index, len(form.DKGIndexMap))
+		}
+		form.DKGIndexMap[index] = nodeID
+	}
+	return form, nil
+}
+
+type epochRecoverCanonicalForm struct {
+	EpochSetup  epochSetupCanonicalForm
+	EpochCommit epochCommitCanonicalForm
+}
+
+func newEpochRecoverCanonicalForm(epochRecover *EpochRecover) (epochRecoverCanonicalForm, error) {
+	setup, err := newEpochSetupCanonicalForm(&epochRecover.EpochSetup)
+	if err != nil {
+		return epochRecoverCanonicalForm{}, err
+	}
+	commit, err := newEpochCommitCanonicalForm(&epochRecover.EpochCommit)
+	if err != nil {
+		return epochRecoverCanonicalForm{}, err
+	}
+	return epochRecoverCanonicalForm{EpochSetup: setup, EpochCommit: commit}, nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// serviceEventIDs are the IDs of the service event vectors, computed with flow-go.
+var serviceEventIDs = map[string]string{
+	flow.ServiceEventEpochSetup:                  "10449a18fd2dec229c69e8542568fae32d8dfcc2b1ef86c097ec60fd50cdc1e7",
+	flow.ServiceEventEpochCommit:                 "3f5c8748d79ae5f8116045328e75bf3cf2ecf99959ca9c854c42c775e0d67977",
+	flow.ServiceEventEpochRecover:                "56da71569138dc1043621b01e143d68a081a5c0199a19ff8a2f6d99d4f3180cb",
+	flow.ServiceEventVersionBeacon:               "5b881bc7abe7d5b9f8d39aa1d8c437d0d936c96f25939a1175b5ded281ed087b",
+	flow.ServiceEventProtocolStateVersionUpgrade: "b59bc5bddb9995eba09b351c25fcd25d618dbfeed80e0ac77cffef0970934530",
+	flow.ServiceEventSetEpochExtensionViewCount:  "98cdf3de1d512551985df5b2be4701be032416f2299628e308355152ff80a811",
+	flow.ServiceEventEjectNode:                   "9476cf2336bf01eb900f66501404dd53ce755a5063afc7dc3837a631bfde80ad",
+}
+
+// testExecutionResult returns an execution result with two chunks and the service event vectors.
+func testExecutionResult(t *testing.T) *flow.ExecutionResult {
+	chunk := func(index uint64, count uint16, start, end byte) *flow.Chunk {
+		return &flow.Chunk{
+			CollectionIndex:      uint(index),
+			StartState:           flow.StateCommitment(testID(start)),
+			EventCollection:      testID(0x10 + byte(index)).Bytes(),
+			BlockID:              testID(0x02),
+			TotalComputationUsed: 1000 + index,
+			NumberOfTransactions: uint16(3 + index),
+			Index:                index,
+			EndState:             flow.StateCommitment(testID(end)),
+			ServiceEventCount:    count,
+		}
+	}
+
+	result := &flow.ExecutionResult{
+		PreviousResultID: testID(0x01),
+		BlockID:          testID(0x02),
+		Chunks:           []*flow.Chunk{chunk(0, 3, 0x20, 0x21), chunk(1, 4, 0x21, 0x22)},
+		ExecutionDataID:  testID(0x03),
+	}
+	for _, vector := range loadServiceEventVectors(t) {
+		result.ServiceEvents = append(result.ServiceEvents, &flow.ServiceEvent{Type: vector.Type, Payload: vector.JSON})
+	}
+	return result
+}
+
+func TestServiceEvent_ID(t *testing.T) {
+	for _, vector := range loadServiceEventVectors(t) {
+		expected := flow.HexToID(serviceEventIDs[vector.Type])
+
+		id, err := flow.ServiceEvent{Type: vector.Type, Payload: vector.JSON}.ID()
+		require.NoError(t, err, vector.Type)
+		assert.Equal(t, expected, id, vector.Type)
+
+		// service events emitted by service contracts have the ID of their protocol representation
+		if vector.EventType != "" {
+			id, err := flow.ServiceEvent{Type: vector.EventType, Payload: vector.CCF}.ID()
+			require.NoError(t, err, vector.EventType)
+			assert.Equal(t, expected, id, vector.EventType)
+		}
+	}
+
+	_, err := flow.ServiceEvent{Type: "unknown", Payload: []byte("{}")}.ID()
+	assert.ErrorIs(t, err, flow.ErrUnknownServiceEvent)
+}
+
+func TestChunk_ID(t *testing.T) {
+	result := testExecutionResult(t)
+	assert.Equal(t,
+		flow.HexToID("f7ccaedcfc605a7ec61114de7fc07b0727e217a1a3ad2b33942817ae87085731"),
+		result.Chunks[0].ID(),
+	)
+	assert.NotEqual(t, result.Chunks[0].ID(), result.Chunks[1].ID())
+}
+
+func TestExecutionResult_ID(t *testing.T) {
+	result := testExecutionResult(t)
+	id, err := result.ID()
+	require.NoError(t, err)
+	assert.Equal(t, flow.HexToID("5dfcacb92a9827d628d46bcf08af969a841a5e86cb04cef75fd0ae02df4a0e75"), id)
+
+	result.ServiceEvents = nil
+	result.Chunks[0].ServiceEventCount = 0
+	result.Chunks[1].ServiceEventCount = 0
+	id, err = result.ID()
+	require.NoError(t, err)
+	assert.Equal(t, flow.HexToID("e3ee3a0d092ff4df9892a93a095e23c4e2a91e99393583f311c0f0fefb54a63f"), id)
+
+	result.ServiceEvents = []*flow.ServiceEvent{{Type: "unknown"}}
+	_, err = result.ID()
+	assert.ErrorIs(t, err, flow.ErrUnknownServiceEvent)
+}
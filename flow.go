@@ -20,6 +20,7 @@ package flow
 
 import (
 	"encoding/hex"
+	"fmt"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/rlp"
@@ -48,6 +49,24 @@ func (i Identifier) String() string {
 	return i.Hex()
 }
 
+// MarshalText returns the hexadecimal text representation of this identifier.
+func (i Identifier) MarshalText() ([]byte, error) {
+	return []byte(i.Hex()), nil
+}
+
+// UnmarshalText decodes an identifier from its hexadecimal text representation.
+func (i *Identifier) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid identifier: %w", err)
+	}
+	if len(b) != len(i) {
+		return fmt.Errorf("invalid identifier length %d, expected %d bytes", len(b), len(i))
+	}
+	copy(i[:], b)
+	return nil
+}
+
 // BytesToID constructs an identifier from a byte slice.
 func BytesToID(b []byte) Identifier {
 	var id Identifier
@@ -0,0 +1,387 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lightclient follows the consensus of the Flow network from a trusted protocol state
+// snapshot, and verifies the finality of block headers without trusting the access nodes
+// serving them.
+//
+// The client verifies the quorum certificate of each header: the aggregated signatures of the
+// consensus committee on the parent of the header, using the committee weights and keys of the
+// epoch of the parent. Headers are finalized according to the consensus rules: a block is
+// finalized when its child has the next view, and the child is certified by a quorum certificate.
+//
+// The client doesn't verify timeout certificates and proposer signatures, which don't
+// contribute to the finality of blocks.
+package lightclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/snapshot"
+)
+
+var (
+	// ErrUnknownParent is returned when the parent of a header isn't known to the client,
+	// or conflicts with the finalized blocks.
+	ErrUnknownParent = errors.New("lightclient: unknown parent")
+	// ErrInvalidHeader is returned when a header is inconsistent with its ID or its parent.
+	ErrInvalidHeader = errors.New("lightclient: invalid header")
+	// ErrInvalidQuorumCertificate is returned when the quorum certificate of a header isn't
+	// signed by a supermajority of the consensus committee.
+	ErrInvalidQuorumCertificate = errors.New("lightclient: invalid quorum certificate")
+	// ErrUnknownEpoch is returned when the epoch of a view isn't known to the client.
+	// The epoch must be added with ProcessServiceEvents.
+	ErrUnknownEpoch = errors.New("lightclient: unknown epoch")
+	// ErrNotFinalized is returned when no header is finalized yet at a height.
+	ErrNotFinalized = errors.New("lightclient: header not finalized")
+	// ErrUnsealedServiceEvents is returned when service events can't be authenticated by a seal
+	// included in a finalized block.
+	ErrUnsealedServiceEvents = errors.New("lightclient: service events not sealed in a finalized block")
+)
+
+// HeaderSource provides finalized block headers, like the Access API clients.
+type HeaderSource interface {
+	GetLatestBlockHeader(ctx context.Context, isSealed bool) (*flow.BlockHeader, error)
+	GetBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.BlockHeader, error)
+}
+
+// Client is a light client following the consensus of the network.
+//
+// It is safe for concurrent use.
+type Client struct {
+	mu sync.RWMutex
+
+	// epochs are the committees of the known epochs, by increasing counter.
+	epochs []*committee
+	// pendingSetup is the setup of the next epoch, until its commit is processed.
+	pendingSetup *flow.EpochSetup
+
+	// finalized are the finalized headers, by height.
+	finalized map[uint64]flow.BlockHeader
+	// latest is the latest finalized header.
+	latest flow.BlockHeader
+	// pending are the verified headers descending from the latest finalized header.
+	pending map[flow.Identifier]flow.BlockHeader
+	// highest is the highest verified header.
+	highest flow.BlockHeader
+}
+
+// New returns a light client bootstrapped from a trusted protocol state snapshot.
+//
+// The head of the snapshot is the first finalized header of the client. Its quorum
+// certificate is verified against the consensus committee of the current epoch of the
+// snapshot, to detect inconsistent snapshots.
+func New(snap *snapshot.Snapshot) (*Client, error) {
+	current, err := newCommittee(snap.CurrentEpoch.Setup, snap.CurrentEpoch.Commit)
+	if err != nil {
+		return nil, fmt.Errorf("lightclient: invalid current epoch: %w", err)
+	}
+
+	c := &Client{
+		epochs:    []*committee{current},
+		finalized: map[uint64]flow.BlockHeader{snap.Head.Height: snap.Head},
+		latest:    snap.Head,
+		pending:   make(map[flow.Identifier]flow.BlockHeader),
+		highest:   snap.Head,
+	}
+
+	if next := snap.NextEpoch; next != nil {
+		if next.Commit == nil {
+			c.pendingSetup = next.Setup
+		} else {
+			committee, err := newCommittee(next.Setup, next.Commit)
+			if err != nil {
+				return nil, fmt.Errorf("lightclient: invalid next epoch: %w", err)
+			}
+			c.epochs = append(c.epochs, committee)
+		}
+	}
+
+	qc := snap.QuorumCertificate
+	if qc.BlockID != snap.Head.ID || qc.View != snap.Head.View {
+		return nil, fmt.Errorf("%w: snapshot quorum certificate doesn't certify the head", ErrInvalidQuorumCertificate)
+	}
+	if err := current.verifyQC(qc); err != nil {
+		return nil, fmt.Errorf("%w: snapshot head: %w", ErrInvalidQuorumCertificate, err)
+	}
+
+	return c, nil
+}
+
+// Append verifies a header and adds it to the client, finalizing its ancestors as
+// permitted by the consensus rules.
+//
+// The parent of the header must have been added before. Appending a known header is a no-op.
+func (c *Client) Append(header flow.BlockHeader) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.pending[header.ID]; ok {
+		return nil
+	}
+	if finalized, ok := c.finalized[header.Height]; ok && finalized.ID == header.ID {
+		return nil
+	}
+
+	if _, err := header.CheckID(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+
+	parent, ok := c.header(header.ParentID)
+	if !ok {
+		return fmt.Errorf("%w: %s of header %s", ErrUnknownParent, header.ParentID, header.ID)
+	}
+	if header.Height != parent.Height+1 {
+		return fmt.Errorf("%w: height %d of header %s doesn't follow parent height %d",
+			ErrInvalidHeader, header.Height, header.ID, parent.Height)
+	}
+	if header.ParentView != parent.View || header.View <= parent.View {
+		return fmt.Errorf("%w: view %d and parent view %d of header %s don't follow parent view %d",
+			ErrInvalidHeader, header.View, header.ParentView, header.ID, parent.View)
+	}
+
+	committee, err := c.committee(header.ParentView)
+	if err != nil {
+		return err
+	}
+	qc := flow.QuorumCertificate{
+		View:          header.ParentView,
+		BlockID:       header.ParentID,
+		SignerIndices: header.ParentVoterIndices,
+		SigData:       header.ParentVoterSigData,
+	}
+	if err := committee.verifyQC(qc); err != nil {
+		return fmt.Errorf("%w: header %s: %w", ErrInvalidQuorumCertificate, header.ID, err)
+	}
+
+	c.pending[header.ID] = header
+	if header.Height > c.highest.Height {
+		c.highest = header
+	}
+
+	// the parent is now certified, which finalizes the grandparent if the parent has the next view
+	if _, ok := c.pending[parent.ID]; ok && parent.View == parent.ParentView+1 {
+		c.finalize(parent.ParentID)
+	}
+
+	return nil
+}
+
+// header returns the finalized or pending header with the given ID.
+func (c *Client) header(id flow.Identifier) (flow.BlockHeader, bool) {
+	if id == c.latest.ID {
+		return c.latest, true
+	}
+	header, ok := c.pending[id]
+	return header, ok
+}
+
+// committee returns the committee of the epoch of the view.
+func (c *Client) committee(view uint64) (*committee, error) {
+	for _, committee := range c.epochs {
+		if committee.includes(view) {
+			return committee, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no epoch includes view %d", ErrUnknownEpoch, view)
+}
+
+// finalize finalizes the pending header with the given ID and its pending ancestors.
+func (c *Client) finalize(id flow.Identifier) {
+	header, ok := c.pending[id]
+	if !ok {
+		return
+	}
+
+	var chain []flow.BlockHeader
+	for ok {
+		chain = append(chain, header)
+		header, ok = c.pending[header.ParentID]
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		c.finalized[chain[i].Height] = chain[i]
+	}
+	c.latest = chain[0]
+
+	// headers at finalized heights are either finalized or conflicting
+	for pendingID, pending := range c.pending {
+		if pending.Height <= c.latest.Height {
+			delete(c.pending, pendingID)
+		}
+	}
+}
+
+// FinalizedHeader returns the verified finalized header at the given height.
+//
+// The error returned for heights that are not finalized yet, or below the snapshot of the
+// client, wraps ErrNotFinalized.
+func (c *Client) FinalizedHeader(height uint64) (*flow.BlockHeader, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	header, ok := c.finalized[height]
+	if !ok {
+		return nil, fmt.Errorf("%w: height %d", ErrNotFinalized, height)
+	}
+	return &header, nil
+}
+
+// LatestFinalizedHeader returns the latest verified finalized header.
+func (c *Client) LatestFinalizedHeader() *flow.BlockHeader {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	header := c.latest
+	return &header
+}
+
+// ProcessServiceEvents follows the epoch transitions of the network, adding the committee of
+// the next epoch from the setup and commit service events of a sealed execution result.
+//
+// Service events are only trustworthy once their result is sealed in a finalized block, so
+// the events are authenticated first: the block must be consistent with its ID and finalized
+// by the client, the seal must be included in the block, and the result must be the sealed
+// result. The service events of the result are then decoded and processed in order, whether
+// they have protocol or Cadence types. Epoch setup, commit and recover events update the
+// committees, events of other types are ignored, and malformed events are errors.
+//
+// The error returned when the block isn't finalized yet wraps ErrNotFinalized. The error
+// returned when the events can't be authenticated wraps ErrUnsealedServiceEvents.
+func (c *Client) ProcessServiceEvents(block *flow.Block, seal *flow.BlockSeal, result *flow.ExecutionResult) error {
+	if err := block.CheckIntegrity(); err != nil {
+		return fmt.Errorf("%w: sealing block %s: %w", ErrUnsealedServiceEvents, block.ID, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	finalized, ok := c.finalized[block.Height]
+	if !ok {
+		return fmt.Errorf("%w: height %d of sealing block %s", ErrNotFinalized, block.Height, block.ID)
+	}
+	if finalized.ID != block.ID {
+		return fmt.Errorf("%w: sealing block %s conflicts with finalized block %s",
+			ErrUnsealedServiceEvents, block.ID, finalized.ID)
+	}
+
+	sealID := seal.ID()
+	included := false
+	for _, blockSeal := range block.Seals {
+		if blockSeal.ID() == sealID {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return fmt.Errorf("%w: seal of block %s not included in sealing block %s",
+			ErrUnsealedServiceEvents, seal.BlockID, block.ID)
+	}
+
+	resultID, err := result.ID()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsealedServiceEvents, err)
+	}
+	if resultID != seal.ResultId {
+		return fmt.Errorf("%w: result %s doesn't match sealed result %s",
+			ErrUnsealedServiceEvents, resultID, seal.ResultId)
+	}
+
+	for _, serviceEvent := range result.ServiceEvents {
+		event, err := serviceEvent.Decode()
+		if errors.Is(err, flow.ErrUnknownServiceEvent) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("lightclient: invalid service event: %w", err)
+		}
+		last := c.epochs[len(c.epochs)-1]
+
+		switch event := event.(type) {
+		case *flow.EpochSetup:
+			if event.Counter <= last.counter {
+				continue
+			}
+			if event.Counter != last.counter+1 {
+				return fmt.Errorf("lightclient: setup of epoch %d doesn't follow epoch %d", event.Counter, last.counter)
+			}
+			c.pendingSetup = event
+
+		case *flow.EpochCommit:
+			if event.Counter <= last.counter {
+				continue
+			}
+			if c.pendingSetup == nil || c.pendingSetup.Counter != event.Counter {
+				return fmt.Errorf("lightclient: commit of epoch %d without its setup", event.Counter)
+			}
+			committee, err := newCommittee(c.pendingSetup, event)
+			if err != nil {
+				return fmt.Errorf("lightclient: invalid epoch %d: %w", event.Counter, err)
+			}
+			c.epochs = append(c.epochs, committee)
+			c.pendingSetup = nil
+
+		case *flow.EpochRecover:
+			if event.EpochSetup.Counter <= last.counter {
+				continue
+			}
+			if event.EpochSetup.Counter != last.counter+1 || event.EpochCommit.Counter != event.EpochSetup.Counter {
+				return fmt.Errorf("lightclient: recovery of epoch %d doesn't follow epoch %d", event.EpochSetup.Counter, last.counter)
+			}
+			committee, err := newCommittee(&event.EpochSetup, &event.EpochCommit)
+			if err != nil {
+				return fmt.Errorf("lightclient: invalid epoch %d: %w", event.EpochSetup.Counter, err)
+			}
+			c.epochs = append(c.epochs, committee)
+			c.pendingSetup = nil
+		}
+	}
+
+	return nil
+}
+
+// Sync appends the finalized headers provided by the source, up to its latest finalized header.
+//
+// The latest headers of the source are verified, but only finalized by the client once
+// their descendants are appended, so the client lags a few blocks behind the source.
+// The error returned when an epoch transition must be processed first wraps ErrUnknownEpoch.
+func (c *Client) Sync(ctx context.Context, source HeaderSource) error {
+	latest, err := source.GetLatestBlockHeader(ctx, false)
+	if err != nil {
+		return fmt.Errorf("lightclient: failed to get latest header: %w", err)
+	}
+
+	c.mu.RLock()
+	next := c.highest.Height + 1
+	c.mu.RUnlock()
+
+	for height := next; height <= latest.Height; height++ {
+		header, err := source.GetBlockHeaderByHeight(ctx, height)
+		if err != nil {
+			return fmt.Errorf("lightclient: failed to get header at height %d: %w", height, err)
+		}
+		if err := c.Append(*header); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
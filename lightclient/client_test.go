@@ -0,0 +1,302 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lightclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/lightclient"
+	"github.com/onflow/flow-go-sdk/snapshot"
+)
+
+// testChain is a chain generated with flow-go: a root snapshot of an epoch ending at view 5,
+// the headers of the following blocks, and the service events of the next epoch, sealed in
+// the block at height 2.
+type testChain struct {
+	Snapshot      json.RawMessage
+	Headers       []flow.BlockHeader
+	ServiceEvents []*flow.ServiceEvent
+	// CadenceServiceEvents are the same service events, with Cadence types and CCF payloads.
+	CadenceServiceEvents []*flow.ServiceEvent
+	// SealingBlock is the block at height 2, sealing Result.
+	SealingBlock *flow.Block
+	Seal         *flow.BlockSeal
+	Result       *flow.ExecutionResult
+}
+
+func loadTestChain(t *testing.T) testChain {
+	data, err := os.ReadFile("testdata/chain.json")
+	require.NoError(t, err)
+
+	var enc struct {
+		Snapshot      json.RawMessage
+		Headers       []flow.BlockHeader
+		ServiceEvents []struct {
+			Type    string
+			Payload json.RawMessage
+		}
+		CadenceServiceEvents []*flow.ServiceEvent
+		Sealing              struct {
+			Height          uint64
+			ProtocolStateID flow.Identifier
+			Seal            *flow.BlockSeal
+			Result          *flow.ExecutionResult
+		}
+	}
+	require.NoError(t, json.Unmarshal(data, &enc))
+
+	chain := testChain{
+		Snapshot:             enc.Snapshot,
+		Headers:              enc.Headers,
+		CadenceServiceEvents: enc.CadenceServiceEvents,
+		Seal:                 enc.Sealing.Seal,
+		Result:               enc.Sealing.Result,
+	}
+	for _, event := range enc.ServiceEvents {
+		chain.ServiceEvents = append(chain.ServiceEvents, &flow.ServiceEvent{Type: event.Type, Payload: event.Payload})
+	}
+	chain.Result.ServiceEvents = chain.ServiceEvents
+	chain.SealingBlock = &flow.Block{
+		BlockHeader: chain.Headers[enc.Sealing.Height-1],
+		BlockPayload: flow.BlockPayload{
+			Seals:                []*flow.BlockSeal{chain.Seal},
+			ExecutionResultsList: []*flow.ExecutionResult{chain.Result},
+			ProtocolStateID:      enc.Sealing.ProtocolStateID,
+		},
+	}
+	return chain
+}
+
+// processServiceEvents processes the service events of the next epoch.
+func (chain testChain) processServiceEvents(client *lightclient.Client) error {
+	return client.ProcessServiceEvents(chain.SealingBlock, chain.Seal, chain.Result)
+}
+
+func newTestClient(t *testing.T, chain testChain) *lightclient.Client {
+	snap, err := snapshot.Decode(chain.Snapshot)
+	require.NoError(t, err)
+
+	client, err := lightclient.New(snap)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_Append(t *testing.T) {
+	chain := loadTestChain(t)
+	client := newTestClient(t, chain)
+
+	root := client.LatestFinalizedHeader()
+	assert.Equal(t, uint64(0), root.Height)
+
+	// blocks are finalized when their child has the next view and is certified
+	expectedFinalized := []uint64{0, 0, 1, 1, 3, 4, 5}
+
+	for i, header := range chain.Headers {
+		if i == 5 {
+			// the quorum certificate of the header is signed by the committee of the next epoch
+			err := client.Append(header)
+			require.ErrorIs(t, err, lightclient.ErrUnknownEpoch)
+			require.NoError(t, chain.processServiceEvents(client))
+		}
+
+		require.NoError(t, client.Append(header), "header %d", header.Height)
+		assert.Equal(t, expectedFinalized[i], client.LatestFinalizedHeader().Height, "header %d", header.Height)
+	}
+
+	for height := uint64(1); height <= 5; height++ {
+		finalized, err := client.FinalizedHeader(height)
+		require.NoError(t, err)
+		assert.Equal(t, chain.Headers[height-1].ID, finalized.ID)
+	}
+	_, err := client.FinalizedHeader(6)
+	assert.ErrorIs(t, err, lightclient.ErrNotFinalized)
+
+	// appending a known header is a no-op
+	require.NoError(t, client.Append(chain.Headers[2]))
+	require.NoError(t, client.Append(chain.Headers[6]))
+}
+
+func TestClient_Append_Invalid(t *testing.T) {
+	chain := loadTestChain(t)
+
+	// modify returns the first header modified, with a consistent ID
+	modify := func(apply func(header *flow.BlockHeader)) flow.BlockHeader {
+		header := chain.Headers[0]
+		header.ParentVoterSigData = append([]byte{}, header.ParentVoterSigData...)
+		header.ParentVoterIndices = append([]byte{}, header.ParentVoterIndices...)
+		apply(&header)
+		header.ID = header.ComputeID(flow.BlockEncodingCurrent)
+		return header
+	}
+
+	t.Run("Unknown parent", func(t *testing.T) {
+		client := newTestClient(t, chain)
+		err := client.Append(chain.Headers[1])
+		assert.ErrorIs(t, err, lightclient.ErrUnknownParent)
+	})
+
+	t.Run("Mismatching ID", func(t *testing.T) {
+		client := newTestClient(t, chain)
+		header := chain.Headers[0]
+		header.Height++
+		err := client.Append(header)
+		assert.ErrorIs(t, err, lightclient.ErrInvalidHeader)
+		assert.ErrorIs(t, err, flow.ErrBlockIDMismatch)
+	})
+
+	t.Run("Mismatching parent view", func(t *testing.T) {
+		client := newTestClient(t, chain)
+		err := client.Append(modify(func(header *flow.BlockHeader) {
+			header.ParentView++
+		}))
+		assert.ErrorIs(t, err, lightclient.ErrInvalidHeader)
+	})
+
+	t.Run("Modified signature", func(t *testing.T) {
+		client := newTestClient(t, chain)
+		err := client.Append(modify(func(header *flow.BlockHeader) {
+			header.ParentVoterSigData[len(header.ParentVoterSigData)-1] ^= 1
+		}))
+		assert.ErrorIs(t, err, lightclient.ErrInvalidQuorumCertificate)
+	})
+
+	t.Run("Insufficient signers", func(t *testing.T) {
+		client := newTestClient(t, chain)
+		err := client.Append(modify(func(header *flow.BlockHeader) {
+			// clear the bit of the last of the three signers
+			header.ParentVoterIndices[4] &^= 0x20
+		}))
+		assert.ErrorIs(t, err, lightclient.ErrInvalidQuorumCertificate)
+	})
+
+	t.Run("Mismatching checksum", func(t *testing.T) {
+		client := newTestClient(t, chain)
+		err := client.Append(modify(func(header *flow.BlockHeader) {
+			header.ParentVoterIndices[0] ^= 1
+		}))
+		assert.ErrorIs(t, err, lightclient.ErrInvalidQuorumCertificate)
+	})
+}
+
+func TestClient_ProcessServiceEvents(t *testing.T) {
+	chain := loadTestChain(t)
+
+	// newClient returns a client which finalized the sealing block
+	newClient := func(t *testing.T) *lightclient.Client {
+		client := newTestClient(t, chain)
+		for _, header := range chain.Headers[:5] {
+			require.NoError(t, client.Append(header))
+		}
+		return client
+	}
+
+	t.Run("Sealed events", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, chain.processServiceEvents(client))
+		// events of known epochs are ignored
+		require.NoError(t, chain.processServiceEvents(client))
+	})
+
+	t.Run("Sealed Cadence events", func(t *testing.T) {
+		client := newClient(t)
+		result := *chain.Result
+		result.ServiceEvents = chain.CadenceServiceEvents
+		require.NoError(t, client.ProcessServiceEvents(chain.SealingBlock, chain.Seal, &result))
+		require.NoError(t, client.Append(chain.Headers[5]))
+	})
+
+	t.Run("Sealing block not finalized", func(t *testing.T) {
+		client := newTestClient(t, chain)
+		err := chain.processServiceEvents(client)
+		assert.ErrorIs(t, err, lightclient.ErrNotFinalized)
+	})
+
+	t.Run("Sealing block not consistent", func(t *testing.T) {
+		client := newClient(t)
+		block := *chain.SealingBlock
+		block.ProtocolStateID = flow.Identifier{}
+		err := client.ProcessServiceEvents(&block, chain.Seal, chain.Result)
+		assert.ErrorIs(t, err, lightclient.ErrUnsealedServiceEvents)
+		assert.ErrorIs(t, err, flow.ErrPayloadHashMismatch)
+	})
+
+	t.Run("Sealing block not finalized at its height", func(t *testing.T) {
+		client := newClient(t)
+		block := flow.Block{BlockHeader: chain.Headers[2], BlockPayload: chain.SealingBlock.BlockPayload}
+		err := client.ProcessServiceEvents(&block, chain.Seal, chain.Result)
+		assert.ErrorIs(t, err, lightclient.ErrUnsealedServiceEvents)
+	})
+
+	// forged is the sealed result with the commit of another committee, and a seal of the forged result
+	forged := *chain.Result
+	var commit map[string]any
+	require.NoError(t, json.Unmarshal(chain.ServiceEvents[1].Payload, &commit))
+	commit["DKGGroupKey"] = commit["DKGParticipantKeys"].([]any)[0]
+	payload, err := json.Marshal(commit)
+	require.NoError(t, err)
+	forged.ServiceEvents = []*flow.ServiceEvent{chain.ServiceEvents[0], {Type: flow.ServiceEventEpochCommit, Payload: payload}}
+	forgedID, err := forged.ID()
+	require.NoError(t, err)
+	forgedSeal := *chain.Seal
+	forgedSeal.ResultId = forgedID
+
+	t.Run("Forged commit", func(t *testing.T) {
+		client := newClient(t)
+		err := client.ProcessServiceEvents(chain.SealingBlock, chain.Seal, &forged)
+		assert.ErrorIs(t, err, lightclient.ErrUnsealedServiceEvents)
+
+		err = client.ProcessServiceEvents(chain.SealingBlock, &forgedSeal, &forged)
+		assert.ErrorIs(t, err, lightclient.ErrUnsealedServiceEvents)
+
+		// the committee of the next epoch is unknown
+		err = client.Append(chain.Headers[5])
+		assert.ErrorIs(t, err, lightclient.ErrUnknownEpoch)
+	})
+}
+
+type testHeaderSource []flow.BlockHeader
+
+func (s testHeaderSource) GetLatestBlockHeader(_ context.Context, _ bool) (*flow.BlockHeader, error) {
+	return &s[len(s)-1], nil
+}
+
+func (s testHeaderSource) GetBlockHeaderByHeight(_ context.Context, height uint64) (*flow.BlockHeader, error) {
+	return &s[height-1], nil
+}
+
+func TestClient_Sync(t *testing.T) {
+	chain := loadTestChain(t)
+	client := newTestClient(t, chain)
+	source := testHeaderSource(chain.Headers)
+
+	err := client.Sync(context.Background(), source)
+	require.ErrorIs(t, err, lightclient.ErrUnknownEpoch)
+	assert.Equal(t, uint64(3), client.LatestFinalizedHeader().Height)
+
+	require.NoError(t, chain.processServiceEvents(client))
+	require.NoError(t, client.Sync(context.Background(), source))
+	assert.Equal(t, uint64(5), client.LatestFinalizedHeader().Height)
+}
@@ -0,0 +1,276 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lightclient
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/onflow/crypto"
+	"github.com/onflow/crypto/hash"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+const (
+	// consensusVoteTag is the domain separation tag of staking signatures on votes.
+	consensusVoteTag = "FLOW-Consensus_Vote-V00-CS00-with-"
+	// randomBeaconTag is the domain separation tag of random beacon signatures on votes.
+	randomBeaconTag = "FLOW-Random_Beacon-V00-CS00-with-"
+	// checksumLen is the length of the committee checksum prefixing signer indices.
+	checksumLen = 4
+)
+
+// committee is the consensus committee of an epoch, voting on the blocks of the views of the epoch.
+type committee struct {
+	counter   uint64
+	firstView uint64
+	finalView uint64
+
+	// members are the voting members of the committee, in canonical order.
+	members flow.IdentityList
+	// checksum is the checksum of the IDs of the members, prefixing signer indices.
+	checksum [checksumLen]byte
+	// threshold is the minimum weight of the signers of a quorum certificate.
+	threshold uint64
+
+	dkgGroupKey  crypto.PublicKey
+	dkgKeyShares map[flow.Identifier]crypto.PublicKey
+	dkgSize      int
+}
+
+// newCommittee returns the committee of the epoch with the given setup and commit.
+//
+// The committee is made of the consensus nodes of the epoch with a positive weight.
+func newCommittee(setup *flow.EpochSetup, commit *flow.EpochCommit) (*committee, error) {
+	if setup.Counter != commit.Counter {
+		return nil, fmt.Errorf("epoch setup counter %d doesn't match commit counter %d", setup.Counter, commit.Counter)
+	}
+
+	members := setup.Participants.Filter(func(identity *flow.Identity) bool {
+		return identity.Role == flow.RoleConsensus && identity.InitialWeight > 0
+	})
+	if len(members) == 0 {
+		return nil, fmt.Errorf("epoch %d has no consensus committee", setup.Counter)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return bytes.Compare(members[i].NodeID[:], members[j].NodeID[:]) < 0
+	})
+
+	ids := make([]byte, 0, len(members)*len(flow.Identifier{}))
+	for _, member := range members {
+		if member.StakingKey == nil {
+			return nil, fmt.Errorf("consensus node %s has no staking key", member.NodeID)
+		}
+		ids = append(ids, member.NodeID[:]...)
+	}
+
+	c := &committee{
+		counter:      setup.Counter,
+		firstView:    setup.FirstView,
+		finalView:    setup.FinalView,
+		members:      members,
+		threshold:    quorumThreshold(members.TotalWeight()),
+		dkgGroupKey:  commit.DKGGroupKey,
+		dkgKeyShares: make(map[flow.Identifier]crypto.PublicKey, len(commit.DKGIndexMap)),
+		dkgSize:      len(commit.DKGParticipantKeys),
+	}
+	binary.BigEndian.PutUint32(c.checksum[:], crc32.ChecksumIEEE(ids))
+
+	if c.dkgGroupKey == nil {
+		return nil, fmt.Errorf("epoch %d has no random beacon group key", setup.Counter)
+	}
+	for nodeID, index := range commit.DKGIndexMap {
+		if index < 0 || index >= c.dkgSize {
+			return nil, fmt.Errorf("random beacon index %d of node %s is out of range", index, nodeID)
+		}
+		c.dkgKeyShares[nodeID] = commit.DKGParticipantKeys[index]
+	}
+
+	return c, nil
+}
+
+// quorumThreshold returns the minimum weight of the signers of a quorum certificate,
+// which is more than 2/3 of the total weight.
+func quorumThreshold(totalWeight uint64) uint64 {
+	// avoid overflows by computing 2*(total/3) + 2*(total%3)/3 + 1
+	return 2*(totalWeight/3) + (2*(totalWeight%3))/3 + 1
+}
+
+// randomBeaconThreshold returns the threshold of the random beacon of the given size:
+// a beacon signature is reconstructed from more than threshold signature shares.
+func randomBeaconThreshold(size int) int {
+	// a beacon of two participants requires both signature shares
+	if size == 2 {
+		return 1
+	}
+	return (size - 1) / 2
+}
+
+// includes returns true if the view is within the views of the epoch of the committee.
+func (c *committee) includes(view uint64) bool {
+	return view >= c.firstView && view <= c.finalView
+}
+
+// sigData is the signature data of a quorum certificate, aggregating the votes of its signers.
+type sigData struct {
+	// SigType is a bit vector indicating, for each signer in order, if it voted with
+	// a staking signature (0) or a random beacon signature (1).
+	SigType                      []byte
+	AggregatedStakingSig         []byte
+	AggregatedRandomBeaconSig    []byte
+	ReconstructedRandomBeaconSig []byte
+}
+
+// verifyQC verifies that the quorum certificate is signed by a supermajority of the committee.
+func (c *committee) verifyQC(qc flow.QuorumCertificate) error {
+	signers, err := c.decodeSigners(qc.SignerIndices)
+	if err != nil {
+		return err
+	}
+
+	weight := signers.TotalWeight()
+	if weight < c.threshold {
+		return fmt.Errorf("signers weight %d is below the quorum threshold %d", weight, c.threshold)
+	}
+
+	var data sigData
+	if err := rlp.DecodeBytes(qc.SigData, &data); err != nil {
+		return fmt.Errorf("invalid signature data: %w", err)
+	}
+	if err := checkPadding(data.SigType, len(signers)); err != nil {
+		return fmt.Errorf("invalid signature types: %w", err)
+	}
+
+	var stakingSigners, beaconSigners flow.IdentityList
+	for i, signer := range signers {
+		if readBit(data.SigType, i) {
+			beaconSigners = append(beaconSigners, signer)
+		} else {
+			stakingSigners = append(stakingSigners, signer)
+		}
+	}
+
+	message := make([]byte, 8, 8+len(qc.BlockID))
+	binary.BigEndian.PutUint64(message, qc.View)
+	message = append(message, qc.BlockID[:]...)
+
+	beaconHasher := crypto.NewExpandMsgXOFKMAC128(randomBeaconTag)
+	valid, err := c.dkgGroupKey.Verify(data.ReconstructedRandomBeaconSig, message, beaconHasher)
+	if err != nil {
+		return fmt.Errorf("failed to verify random beacon signature: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("invalid random beacon signature")
+	}
+
+	threshold := randomBeaconThreshold(c.dkgSize)
+	if len(beaconSigners) <= threshold {
+		return fmt.Errorf("%d random beacon signers, at least %d required", len(beaconSigners), threshold+1)
+	}
+	beaconKeys := make([]crypto.PublicKey, 0, len(beaconSigners))
+	for _, signer := range beaconSigners {
+		key, ok := c.dkgKeyShares[signer.NodeID]
+		if !ok {
+			return fmt.Errorf("signer %s is not a random beacon participant", signer.NodeID)
+		}
+		beaconKeys = append(beaconKeys, key)
+	}
+	if err := verifyAggregatedSignature(beaconKeys, data.AggregatedRandomBeaconSig, message, beaconHasher); err != nil {
+		return fmt.Errorf("invalid aggregated random beacon signature: %w", err)
+	}
+
+	if len(stakingSigners) == 0 {
+		if len(data.AggregatedStakingSig) > 0 {
+			return fmt.Errorf("unexpected aggregated staking signature without staking signers")
+		}
+		return nil
+	}
+	stakingKeys := make([]crypto.PublicKey, 0, len(stakingSigners))
+	for _, signer := range stakingSigners {
+		stakingKeys = append(stakingKeys, signer.StakingKey)
+	}
+	stakingHasher := crypto.NewExpandMsgXOFKMAC128(consensusVoteTag)
+	if err := verifyAggregatedSignature(stakingKeys, data.AggregatedStakingSig, message, stakingHasher); err != nil {
+		return fmt.Errorf("invalid aggregated staking signature: %w", err)
+	}
+
+	return nil
+}
+
+// decodeSigners returns the committee members designated by signer indices: a checksum of
+// the committee, followed by a bit vector indicating which members signed.
+func (c *committee) decodeSigners(signerIndices []byte) (flow.IdentityList, error) {
+	if len(signerIndices) < checksumLen {
+		return nil, fmt.Errorf("signer indices are too short")
+	}
+	if !bytes.Equal(signerIndices[:checksumLen], c.checksum[:]) {
+		return nil, fmt.Errorf("signer indices checksum doesn't match the committee of epoch %d", c.counter)
+	}
+
+	bits := signerIndices[checksumLen:]
+	if err := checkPadding(bits, len(c.members)); err != nil {
+		return nil, fmt.Errorf("invalid signer indices: %w", err)
+	}
+
+	var signers flow.IdentityList
+	for i, member := range c.members {
+		if readBit(bits, i) {
+			signers = append(signers, member)
+		}
+	}
+	return signers, nil
+}
+
+// checkPadding checks that the bit vector has the minimal length to hold the given number of
+// bits, and that its padding bits are zero.
+func checkPadding(bits []byte, count int) error {
+	if len(bits) != (count+7)/8 {
+		return fmt.Errorf("bit vector has %d bytes, expected %d bytes for %d bits", len(bits), (count+7)/8, count)
+	}
+	if count%8 != 0 && bits[len(bits)-1]<<(count%8) != 0 {
+		return fmt.Errorf("bit vector has non-zero padding")
+	}
+	return nil
+}
+
+// readBit returns the bit at the given index of the bit vector, starting from the most significant bit.
+func readBit(bits []byte, index int) bool {
+	return bits[index/8]&(1<<(7-index%8)) != 0
+}
+
+// verifyAggregatedSignature verifies a BLS signature aggregating the signatures of the keys on the message.
+func verifyAggregatedSignature(keys []crypto.PublicKey, signature []byte, message []byte, hasher hash.Hasher) error {
+	key, err := crypto.AggregateBLSPublicKeys(keys)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate keys: %w", err)
+	}
+	valid, err := key.Verify(signature, message, hasher)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("signature doesn't match the signers")
+	}
+	return nil
+}
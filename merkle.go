@@ -0,0 +1,124 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Node tags of the Patricia Merkle tree used by the Flow protocol to commit to lists of identifiers.
+var (
+	merkleLeafTag  = []byte{0}
+	merkleFullTag  = []byte{1}
+	merkleShortTag = []byte{2}
+)
+
+const identifierBits = len(Identifier{}) * 8
+
+type merkleEntry struct {
+	key   Identifier
+	value []byte
+}
+
+// MerkleRoot returns the root hash of the Merkle tree mapping each identifier to its
+// index in the list, as computed by the Flow protocol for block payloads.
+//
+// When an identifier is repeated, its last index is used.
+func MerkleRoot(ids ...Identifier) Identifier {
+	indexes := make(map[Identifier]int, len(ids))
+	for i, id := range ids {
+		indexes[id] = i
+	}
+
+	entries := make([]merkleEntry, 0, len(indexes))
+	for id, i := range indexes {
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, uint64(i))
+		entries = append(entries, merkleEntry{key: id, value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key[:], entries[j].key[:]) < 0
+	})
+
+	if len(entries) == 0 {
+		h, _ := blake2b.New256(nil)
+		return HashToID(h.Sum(nil))
+	}
+	return HashToID(merkleNodeHash(entries, 0))
+}
+
+// merkleNodeHash returns the hash of the node holding the given sorted entries, whose keys
+// share the bits before the given index. The tree is compressed: a path segment shared by
+// all the entries of a node is stored in a single short node.
+func merkleNodeHash(entries []merkleEntry, index int) []byte {
+	if index == identifierBits {
+		h, _ := blake2b.New256(merkleLeafTag)
+		_, _ = h.Write(entries[0].value)
+		return h.Sum(nil)
+	}
+
+	// the first and last keys bound the common prefix of the sorted keys
+	first, last := entries[0].key[:], entries[len(entries)-1].key[:]
+	count := 0
+	for index+count < identifierBits && readBit(first, index+count) == readBit(last, index+count) {
+		count++
+	}
+
+	if count > 0 {
+		path := make([]byte, (count+7)/8)
+		for i := 0; i < count; i++ {
+			if readBit(first, index+i) == 1 {
+				path[i/8] |= 1 << (7 - i%8)
+			}
+		}
+
+		h, _ := blake2b.New256(merkleShortTag)
+		_, _ = h.Write([]byte{byte(count >> 8), byte(count)})
+		_, _ = h.Write(path)
+		_, _ = h.Write(merkleNodeHash(entries, index+count))
+		return h.Sum(nil)
+	}
+
+	split := sort.Search(len(entries), func(i int) bool {
+		return readBit(entries[i].key[:], index) == 1
+	})
+
+	h, _ := blake2b.New256(merkleFullTag)
+	_, _ = h.Write(merkleNodeHash(entries[:split], index+1))
+	_, _ = h.Write(merkleNodeHash(entries[split:], index+1))
+	return h.Sum(nil)
+}
+
+// readBit returns the bit at the given index, starting from the most significant bit of the first byte.
+func readBit(b []byte, index int) int {
+	return int(b[index/8]>>(7-index%8)) & 1
+}
+
+// concatSum returns the SHA3-256 hash of the concatenated identifiers.
+func concatSum(ids ...Identifier) Identifier {
+	var b []byte
+	for _, id := range ids {
+		b = append(b, id[:]...)
+	}
+	return HashToID(defaultEntityHasher.ComputeHash(b))
+}
@@ -0,0 +1,387 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownNetwork is returned when no network is registered for a chain ID.
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// ErrUnresolvedImport is returned when a Cadence import cannot be resolved to an address.
+var ErrUnresolvedImport = errors.New("unresolved import")
+
+// Names of the system and core contracts deployed on every network.
+const (
+	ContractFlowEpoch                  = "FlowEpoch"
+	ContractFlowIDTableStaking         = "FlowIDTableStaking"
+	ContractFlowClusterQC              = "FlowClusterQC"
+	ContractFlowDKG                    = "FlowDKG"
+	ContractFlowServiceAccount         = "FlowServiceAccount"
+	ContractFlowTransactionScheduler   = "FlowTransactionScheduler"
+	ContractNodeVersionBeacon          = "NodeVersionBeacon"
+	ContractRandomBeaconHistory        = "RandomBeaconHistory"
+	ContractFlowStorageFees            = "FlowStorageFees"
+	ContractFlowFees                   = "FlowFees"
+	ContractFlowToken                  = "FlowToken"
+	ContractFungibleToken              = "FungibleToken"
+	ContractFungibleTokenMetadataViews = "FungibleTokenMetadataViews"
+	ContractFungibleTokenSwitchboard   = "FungibleTokenSwitchboard"
+	ContractNonFungibleToken           = "NonFungibleToken"
+	ContractMetadataViews              = "MetadataViews"
+	ContractViewResolver               = "ViewResolver"
+	ContractCrossVMMetadataViews       = "CrossVMMetadataViews"
+	ContractEVM                        = "EVM"
+	ContractBurner                     = "Burner"
+	ContractCrypto                     = "Crypto"
+)
+
+// Hosts of the public access node APIs of the networks.
+const (
+	MainnetGRPCHost  = "access.mainnet.nodes.onflow.org:9000"
+	MainnetRESTHost  = "https://rest-mainnet.onflow.org/v1"
+	TestnetGRPCHost  = "access.devnet.nodes.onflow.org:9000"
+	TestnetRESTHost  = "https://rest-testnet.onflow.org/v1"
+	EmulatorGRPCHost = "127.0.0.1:3569"
+	EmulatorRESTHost = "http://127.0.0.1:8888/v1"
+)
+
+// A Network describes a Flow network: the public access node endpoints serving it and
+// the addresses its system and core contracts are deployed to.
+type Network struct {
+	ChainID ChainID
+	// GRPCHost is the host of the public access node gRPC API, or empty if there is none.
+	GRPCHost string
+	// RESTHost is the base URL of the public access node REST API, or empty if there is none.
+	RESTHost string
+	// Contracts maps contract names to the address they are deployed to.
+	Contracts map[string]Address
+}
+
+// ContractAddress returns the address the named contract is deployed to on the network.
+func (n *Network) ContractAddress(name string) (Address, bool) {
+	address, ok := n.Contracts[name]
+	return address, ok
+}
+
+// copy returns a deep copy of the network, so that registry entries cannot be
+// modified by callers.
+func (n *Network) copy() *Network {
+	contracts := make(map[string]Address, len(n.Contracts))
+	for name, address := range n.Contracts {
+		contracts[name] = address
+	}
+	network := *n
+	network.Contracts = contracts
+	return &network
+}
+
+var (
+	networksMu sync.RWMutex
+	networks   = map[ChainID]*Network{}
+)
+
+func init() {
+	mainnetStaking := HexToAddress("8624b52f9ddcd04a")
+	mainnetNFT := HexToAddress("1d7e57aa55817448")
+	mainnetFT := HexToAddress("f233dcee88fe0abe")
+	mainnetService := ServiceAddress(Mainnet)
+
+	networks[Mainnet] = &Network{
+		ChainID:  Mainnet,
+		GRPCHost: MainnetGRPCHost,
+		RESTHost: MainnetRESTHost,
+		Contracts: map[string]Address{
+			ContractFlowEpoch:                  mainnetStaking,
+			ContractFlowIDTableStaking:         mainnetStaking,
+			ContractFlowClusterQC:              mainnetStaking,
+			ContractFlowDKG:                    mainnetStaking,
+			ContractFlowServiceAccount:         mainnetService,
+			ContractFlowTransactionScheduler:   mainnetService,
+			ContractNodeVersionBeacon:          mainnetService,
+			ContractRandomBeaconHistory:        mainnetService,
+			ContractFlowStorageFees:            mainnetService,
+			ContractFlowFees:                   HexToAddress("f919ee77447b7497"),
+			ContractFlowToken:                  HexToAddress("1654653399040a61"),
+			ContractFungibleToken:              mainnetFT,
+			ContractFungibleTokenMetadataViews: mainnetFT,
+			ContractFungibleTokenSwitchboard:   mainnetFT,
+			ContractNonFungibleToken:           mainnetNFT,
+			ContractMetadataViews:              mainnetNFT,
+			ContractViewResolver:               mainnetNFT,
+			ContractCrossVMMetadataViews:       mainnetNFT,
+			ContractEVM:                        mainnetService,
+			ContractBurner:                     mainnetFT,
+			ContractCrypto:                     mainnetService,
+		},
+	}
+
+	testnetStaking := HexToAddress("9eca2b38b18b5dfe")
+	testnetNFT := HexToAddress("631e88ae7f1d7c20")
+	testnetFT := HexToAddress("9a0766d93b6608b7")
+	testnetService := ServiceAddress(Testnet)
+
+	networks[Testnet] = &Network{
+		ChainID:  Testnet,
+		GRPCHost: TestnetGRPCHost,
+		RESTHost: TestnetRESTHost,
+		Contracts: map[string]Address{
+			ContractFlowEpoch:                  testnetStaking,
+			ContractFlowIDTableStaking:         testnetStaking,
+			ContractFlowClusterQC:              testnetStaking,
+			ContractFlowDKG:                    testnetStaking,
+			ContractFlowServiceAccount:         testnetService,
+			ContractFlowTransactionScheduler:   testnetService,
+			ContractNodeVersionBeacon:          testnetService,
+			ContractRandomBeaconHistory:        testnetService,
+			ContractFlowStorageFees:            testnetService,
+			ContractFlowFees:                   HexToAddress("912d5440f7e3769e"),
+			ContractFlowToken:                  HexToAddress("7e60df042a9c0868"),
+			ContractFungibleToken:              testnetFT,
+			ContractFungibleTokenMetadataViews: testnetFT,
+			ContractFungibleTokenSwitchboard:   testnetFT,
+			ContractNonFungibleToken:           testnetNFT,
+			ContractMetadataViews:              testnetNFT,
+			ContractViewResolver:               testnetNFT,
+			ContractCrossVMMetadataViews:       testnetNFT,
+			ContractEVM:                        testnetService,
+			ContractBurner:                     testnetFT,
+			ContractCrypto:                     testnetService,
+		},
+	}
+
+	for _, chain := range []ChainID{Emulator, Localnet, Benchnet, BftTestnet, MonotonicEmulator} {
+		networks[chain] = &Network{
+			ChainID:   chain,
+			Contracts: transientNetworkContracts(chain),
+		}
+	}
+	networks[Emulator].GRPCHost = EmulatorGRPCHost
+	networks[Emulator].RESTHost = EmulatorRESTHost
+}
+
+// transientNetworkContracts returns the contract addresses of a transient network.
+//
+// Transient networks are bootstrapped with the service account, followed by the
+// fungible token, Flow token and fees accounts, in order of address generation.
+// All other contracts are deployed to the service account.
+func transientNetworkContracts(chain ChainID) map[string]Address {
+	service := generateAddress(chain, serviceAddressState)
+	fungibleToken := generateAddress(chain, serviceAddressState+1)
+
+	return map[string]Address{
+		ContractFlowEpoch:                  service,
+		ContractFlowIDTableStaking:         service,
+		ContractFlowClusterQC:              service,
+		ContractFlowDKG:                    service,
+		ContractFlowServiceAccount:         service,
+		ContractFlowTransactionScheduler:   service,
+		ContractNodeVersionBeacon:          service,
+		ContractRandomBeaconHistory:        service,
+		ContractFlowStorageFees:            service,
+		ContractFlowFees:                   generateAddress(chain, serviceAddressState+3),
+		ContractFlowToken:                  generateAddress(chain, serviceAddressState+2),
+		ContractFungibleToken:              fungibleToken,
+		ContractFungibleTokenMetadataViews: fungibleToken,
+		ContractFungibleTokenSwitchboard:   fungibleToken,
+		ContractNonFungibleToken:           service,
+		ContractMetadataViews:              service,
+		ContractViewResolver:               service,
+		ContractCrossVMMetadataViews:       service,
+		ContractEVM:                        service,
+		ContractBurner:                     service,
+		ContractCrypto:                     service,
+	}
+}
+
+// GetNetwork returns the network registered for the given chain ID.
+//
+// The returned network is a copy and can be modified freely.
+func GetNetwork(chainID ChainID) (*Network, error) {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+
+	network, ok := networks[chainID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNetwork, chainID)
+	}
+	return network.copy(), nil
+}
+
+// Networks returns all registered networks, ordered by chain ID.
+func Networks() []*Network {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+
+	result := make([]*Network, 0, len(networks))
+	for _, network := range networks {
+		result = append(result, network.copy())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ChainID < result[j].ChainID
+	})
+	return result
+}
+
+// RegisterNetwork adds a network to the registry, replacing any network already
+// registered for its chain ID.
+func RegisterNetwork(network *Network) {
+	networksMu.Lock()
+	defer networksMu.Unlock()
+
+	networks[network.ChainID] = network.copy()
+}
+
+// An ImportResolver rewrites the imports of Cadence code to the contract addresses
+// of a network.
+type ImportResolver struct {
+	network   *Network
+	overrides map[string]Address
+}
+
+// NewImportResolver creates an import resolver for the network registered for the
+// given chain ID.
+func NewImportResolver(chainID ChainID) (*ImportResolver, error) {
+	network, err := GetNetwork(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportResolver{
+		network:   network,
+		overrides: make(map[string]Address),
+	}, nil
+}
+
+// Override resolves imports of the named contract to the given address, taking
+// precedence over the network's contract addresses.
+func (r *ImportResolver) Override(name string, address Address) *ImportResolver {
+	r.overrides[name] = address
+	return r
+}
+
+// address returns the address the named contract resolves to.
+func (r *ImportResolver) address(name string) (Address, bool) {
+	if address, ok := r.overrides[name]; ok {
+		return address, true
+	}
+	return r.network.ContractAddress(name)
+}
+
+// importPattern matches string imports (`import "FungibleToken"`) and address
+// imports (`import FungibleToken from 0xFUNGIBLETOKEN`) at the start of a line.
+var importPattern = regexp.MustCompile(
+	`(?m)^([ \t]*)import[ \t]+(?:"([^"\n]*)"|([A-Za-z_]\w*(?:[ \t]*,[ \t]*[A-Za-z_]\w*)*)[ \t]+from[ \t]+(0x\w+))`,
+)
+
+// Resolve rewrites the imports in the given Cadence code to the contract addresses
+// of the network.
+//
+// String imports (`import "FungibleToken"`) are rewritten to address imports.
+// Address imports of known contracts from a placeholder (`import FungibleToken from
+// 0xFUNGIBLETOKEN`), or from the address of the contract on another registered network,
+// have their address replaced, so code written for another network can be run on the
+// target network. Other address imports are left unchanged, since the address was chosen
+// by the author of the code, even if the contract name is the name of a known contract.
+//
+// An error wrapping ErrUnresolvedImport is returned if a string import, or an import
+// from a placeholder that is not a valid address, names an unknown contract.
+func (r *ImportResolver) Resolve(code []byte) ([]byte, error) {
+	var err error
+	resolved := importPattern.ReplaceAllFunc(code, func(match []byte) []byte {
+		if err != nil {
+			return match
+		}
+		groups := importPattern.FindSubmatch(match)
+		indent, location := string(groups[1]), string(groups[4])
+
+		// string import
+		if groups[3] == nil {
+			name := string(groups[2])
+			address, ok := r.address(name)
+			if !ok {
+				err = fmt.Errorf("%w: %s on %s", ErrUnresolvedImport, name, r.network.ChainID)
+				return match
+			}
+			return []byte(fmt.Sprintf("%simport %s from %s", indent, name, address.HexWithPrefix()))
+		}
+
+		// address import, split by address if the contracts are deployed to different accounts
+		var imports []string
+		byAddress := make(map[string][]string)
+		for _, name := range strings.Split(string(groups[3]), ",") {
+			name = strings.TrimSpace(name)
+			target := location
+			address, known := r.address(name)
+			placeholder := !isHexAddress(location) || isNetworkContractAddress(name, HexToAddress(location))
+			if known && placeholder {
+				target = address.HexWithPrefix()
+			} else if !isHexAddress(location) {
+				err = fmt.Errorf("%w: %s from %s on %s", ErrUnresolvedImport, name, location, r.network.ChainID)
+				return match
+			}
+			if _, ok := byAddress[target]; !ok {
+				imports = append(imports, target)
+			}
+			byAddress[target] = append(byAddress[target], name)
+		}
+
+		lines := make([]string, len(imports))
+		for i, target := range imports {
+			lines[i] = fmt.Sprintf("%simport %s from %s", indent, strings.Join(byAddress[target], ", "), target)
+		}
+		return []byte(strings.Join(lines, "\n"))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// isNetworkContractAddress returns true if the named contract is deployed to the address
+// on any registered network.
+func isNetworkContractAddress(name string, address Address) bool {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+
+	for _, network := range networks {
+		if deployed, ok := network.Contracts[name]; ok && deployed == address {
+			return true
+		}
+	}
+	return false
+}
+
+// isHexAddress returns true if the location is a literal account address.
+func isHexAddress(location string) bool {
+	hex := strings.TrimPrefix(location, "0x")
+	if len(hex) == 0 || len(hex) > 2*AddressLength {
+		return false
+	}
+	for _, c := range hex {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,189 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+func TestGetNetwork(t *testing.T) {
+	// addresses as deployed by flow-go
+	expected := map[flow.ChainID]map[string]string{
+		flow.Mainnet: {
+			flow.ContractFlowEpoch:        "8624b52f9ddcd04a",
+			flow.ContractFlowFees:         "f919ee77447b7497",
+			flow.ContractFlowToken:        "1654653399040a61",
+			flow.ContractFungibleToken:    "f233dcee88fe0abe",
+			flow.ContractNonFungibleToken: "1d7e57aa55817448",
+			flow.ContractEVM:              "e467b9dd11fa00df",
+		},
+		flow.Testnet: {
+			flow.ContractFlowEpoch:        "9eca2b38b18b5dfe",
+			flow.ContractFlowFees:         "912d5440f7e3769e",
+			flow.ContractFlowToken:        "7e60df042a9c0868",
+			flow.ContractFungibleToken:    "9a0766d93b6608b7",
+			flow.ContractNonFungibleToken: "631e88ae7f1d7c20",
+			flow.ContractEVM:              "8c5303eaa26202d6",
+		},
+		flow.Emulator: {
+			flow.ContractFlowEpoch:        "f8d6e0586b0a20c7",
+			flow.ContractFlowFees:         "e5a8b7f23e8b548f",
+			flow.ContractFlowToken:        "0ae53cb6e3f42a79",
+			flow.ContractFungibleToken:    "ee82856bf20e2aa6",
+			flow.ContractNonFungibleToken: "f8d6e0586b0a20c7",
+			flow.ContractBurner:           "f8d6e0586b0a20c7",
+		},
+		flow.MonotonicEmulator: {
+			flow.ContractFlowEpoch:     "0000000000000001",
+			flow.ContractFlowFees:      "0000000000000004",
+			flow.ContractFlowToken:     "0000000000000003",
+			flow.ContractFungibleToken: "0000000000000002",
+		},
+	}
+
+	for chain, contracts := range expected {
+		network, err := flow.GetNetwork(chain)
+		require.NoError(t, err)
+		assert.Equal(t, chain, network.ChainID)
+
+		for name, address := range contracts {
+			actual, ok := network.ContractAddress(name)
+			require.True(t, ok, name)
+			assert.Equal(t, flow.HexToAddress(address), actual, "%s on %s", name, chain)
+		}
+	}
+
+	mainnet, err := flow.GetNetwork(flow.Mainnet)
+	require.NoError(t, err)
+	assert.Equal(t, "access.mainnet.nodes.onflow.org:9000", mainnet.GRPCHost)
+	assert.Equal(t, "https://rest-mainnet.onflow.org/v1", mainnet.RESTHost)
+
+	_, err = flow.GetNetwork("flow-unknown")
+	assert.ErrorIs(t, err, flow.ErrUnknownNetwork)
+}
+
+func TestRegisterNetwork(t *testing.T) {
+	chain := flow.ChainID("flow-test-register")
+	address := flow.HexToAddress("01")
+	flow.RegisterNetwork(&flow.Network{
+		ChainID:   chain,
+		GRPCHost:  "localhost:3569",
+		Contracts: map[string]flow.Address{"Foo": address},
+	})
+
+	network, err := flow.GetNetwork(chain)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:3569", network.GRPCHost)
+
+	// registry entries are not modified through returned networks
+	network.Contracts["Foo"] = flow.EmptyAddress
+	network, err = flow.GetNetwork(chain)
+	require.NoError(t, err)
+	assert.Equal(t, address, network.Contracts["Foo"])
+
+	assert.Contains(t, flow.Networks(), network)
+}
+
+func TestImportResolver_Resolve(t *testing.T) {
+	resolver, err := flow.NewImportResolver(flow.Testnet)
+	require.NoError(t, err)
+
+	t.Run("String imports", func(t *testing.T) {
+		code := `import "FungibleToken"
+import "FlowToken"
+
+access(all) fun main() {}
+`
+		resolved, err := resolver.Resolve([]byte(code))
+		require.NoError(t, err)
+		assert.Equal(t, `import FungibleToken from 0x9a0766d93b6608b7
+import FlowToken from 0x7e60df042a9c0868
+
+access(all) fun main() {}
+`, string(resolved))
+	})
+
+	t.Run("Address imports", func(t *testing.T) {
+		code := `import FungibleToken from 0xFUNGIBLETOKENADDRESS
+  import FlowToken from 0x1654653399040a61
+import Foo from 0x01
+import Crypto
+`
+		resolved, err := resolver.Resolve([]byte(code))
+		require.NoError(t, err)
+		assert.Equal(t, `import FungibleToken from 0x9a0766d93b6608b7
+  import FlowToken from 0x7e60df042a9c0868
+import Foo from 0x01
+import Crypto
+`, string(resolved))
+	})
+
+	t.Run("Multiple contracts", func(t *testing.T) {
+		resolved, err := resolver.Resolve([]byte("import NonFungibleToken, MetadataViews, FlowToken from 0xCONTRACTS"))
+		require.NoError(t, err)
+		assert.Equal(t,
+			"import NonFungibleToken, MetadataViews from 0x631e88ae7f1d7c20\nimport FlowToken from 0x7e60df042a9c0868",
+			string(resolved),
+		)
+	})
+
+	t.Run("Explicit addresses", func(t *testing.T) {
+		code := `import MetadataViews from 0xabcdef0123456789
+import Burner from 0x0123456789abcdef
+import FungibleToken from 0xee82856bf20e2aa6
+`
+		resolved, err := resolver.Resolve([]byte(code))
+		require.NoError(t, err)
+		// only the emulator address of the fungible token contract is replaced
+		assert.Equal(t, `import MetadataViews from 0xabcdef0123456789
+import Burner from 0x0123456789abcdef
+import FungibleToken from 0x9a0766d93b6608b7
+`, string(resolved))
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		resolver, err := flow.NewImportResolver(flow.Emulator)
+		require.NoError(t, err)
+		resolver.
+			Override("Foo", flow.HexToAddress("01cf0e2f2f715450")).
+			Override(flow.ContractFlowToken, flow.HexToAddress("179b6b1cb6755e31"))
+
+		resolved, err := resolver.Resolve([]byte("import \"Foo\"\nimport FlowToken from 0xFLOWTOKEN"))
+		require.NoError(t, err)
+		assert.Equal(t,
+			"import Foo from 0x01cf0e2f2f715450\nimport FlowToken from 0x179b6b1cb6755e31",
+			string(resolved),
+		)
+	})
+
+	t.Run("Unresolved imports", func(t *testing.T) {
+		_, err := resolver.Resolve([]byte(`import "Foo"`))
+		assert.ErrorIs(t, err, flow.ErrUnresolvedImport)
+
+		_, err = resolver.Resolve([]byte(`import Foo from 0xFOO`))
+		assert.ErrorIs(t, err, flow.ErrUnresolvedImport)
+	})
+
+	_, err = flow.NewImportResolver("flow-unknown")
+	assert.ErrorIs(t, err, flow.ErrUnknownNetwork)
+}
@@ -0,0 +1,341 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Key part types of the payloads of trie updates.
+const (
+	// KeyPartOwner is the type of the key part holding the owner of a register, the address of
+	// an account, or empty for the registers of the execution environment.
+	KeyPartOwner uint16 = 0
+	// KeyPartKey is the type of the key part holding the key of a register.
+	KeyPartKey uint16 = 2
+)
+
+// Register keys of accounts, as laid out by the execution environment.
+const (
+	accountStatusRegisterKey         = "a.s"
+	contractNamesRegisterKey         = "contract_names"
+	contractCodeRegisterKeyPrefix    = "code."
+	publicKey0RegisterKey            = "apk_0"
+	batchPublicKeyRegisterKeyPrefix  = "pk_b"
+	legacyPublicKeyRegisterKeyPrefix = "public_key_"
+	sequenceNumberRegisterKeyPrefix  = "sn_"
+	accountStorageRegisterKey        = "stored"
+	storageSlabRegisterKeyPrefix     = '$'
+	storageSlabRegisterKeyLength     = 9
+	uuidRegisterKeyPrefix            = "uuid"
+	addressStateRegisterKey          = "account_address_state"
+)
+
+// storageDomains are the registers of the storage domains of accounts, by domain identifier.
+var storageDomains = map[string]bool{
+	"storage":  true,
+	"private":  true,
+	"public":   true,
+	"contract": true,
+	"inbox":    true,
+	"cap_con":  true,
+	"cap_tag":  true,
+	"path_cap": true,
+	"acc_cap":  true,
+}
+
+// RegisterKind is the classification of a register by its key.
+type RegisterKind int
+
+const (
+	// RegisterKindUnknown is a register with a key of an unknown layout.
+	RegisterKindUnknown RegisterKind = iota
+	// RegisterKindGlobal is a register of the execution environment, not owned by an account,
+	// such as the UUID generator.
+	RegisterKindGlobal
+	// RegisterKindAccountStatus is the status of an account, decoded with DecodeAccountStatusRegister.
+	RegisterKindAccountStatus
+	// RegisterKindPublicKey is a public key of an account, or a batch of public keys.
+	RegisterKindPublicKey
+	// RegisterKindSequenceNumber is the sequence number of a public key of an account.
+	RegisterKindSequenceNumber
+	// RegisterKindContractCode is the code of a contract of an account.
+	RegisterKindContractCode
+	// RegisterKindContractNames is the list of the names of the contracts of an account.
+	RegisterKindContractNames
+	// RegisterKindStorageDomain is the root of a storage domain of an account, such as the
+	// storage or public domains, or the root of all the domains of the account.
+	RegisterKindStorageDomain
+	// RegisterKindStorageSlab is a slab of the Cadence values stored by an account.
+	RegisterKindStorageSlab
+)
+
+func (k RegisterKind) String() string {
+	switch k {
+	case RegisterKindGlobal:
+		return "global"
+	case RegisterKindAccountStatus:
+		return "account status"
+	case RegisterKindPublicKey:
+		return "public key"
+	case RegisterKindSequenceNumber:
+		return "sequence number"
+	case RegisterKindContractCode:
+		return "contract code"
+	case RegisterKindContractNames:
+		return "contract names"
+	case RegisterKindStorageDomain:
+		return "storage domain"
+	case RegisterKindStorageSlab:
+		return "storage slab"
+	default:
+		return "unknown"
+	}
+}
+
+// RegisterID identifies a register by its owner and key.
+type RegisterID struct {
+	// Owner is the address of the account owning the register, or EmptyAddress for the
+	// registers of the execution environment.
+	Owner Address
+	// Key is the key of the register, which isn't always printable.
+	Key string
+}
+
+// RegisterChange is the value written to a register by a trie update.
+type RegisterChange struct {
+	RegisterID
+	Kind RegisterKind
+	// Name is the name of the contract for contract code registers, and the identifier of the
+	// domain for storage domain registers.
+	Name string
+	// Index is the index of the public key, of the batch of public keys for batch public key
+	// registers, of the public key of the sequence number, or of the storage slab.
+	Index uint64
+	// Value is the new value of the register, empty when the register is removed.
+	Value []byte
+}
+
+// DecodePayload decodes the register change of a trie update payload.
+func DecodePayload(payload *Payload) (RegisterChange, error) {
+	var (
+		owner, key       []byte
+		hasOwner, hasKey bool
+	)
+	for _, part := range payload.KeyPart {
+		switch part.Type {
+		case KeyPartOwner:
+			owner, hasOwner = part.Value, true
+		case KeyPartKey:
+			key, hasKey = part.Value, true
+		default:
+			return RegisterChange{}, fmt.Errorf("invalid register key part type %d", part.Type)
+		}
+	}
+	if !hasOwner || !hasKey {
+		return RegisterChange{}, fmt.Errorf("register key must have an owner and a key part")
+	}
+	if len(owner) != 0 && len(owner) != AddressLength {
+		return RegisterChange{}, fmt.Errorf("invalid register owner length %d", len(owner))
+	}
+
+	change := RegisterChange{
+		RegisterID: RegisterID{
+			Owner: BytesToAddress(owner),
+			Key:   string(key),
+		},
+		Value: payload.Value,
+	}
+	change.Kind, change.Name, change.Index = classifyRegister(len(owner) == 0, change.Key)
+	return change, nil
+}
+
+// classifyRegister returns the kind of a register from its key, with the name or the index
+// embedded in the key.
+func classifyRegister(global bool, key string) (RegisterKind, string, uint64) {
+	if global {
+		if strings.HasPrefix(key, uuidRegisterKeyPrefix) || key == addressStateRegisterKey {
+			return RegisterKindGlobal, "", 0
+		}
+		return RegisterKindUnknown, "", 0
+	}
+
+	switch {
+	case key == accountStatusRegisterKey:
+		return RegisterKindAccountStatus, "", 0
+	case key == contractNamesRegisterKey:
+		return RegisterKindContractNames, "", 0
+	case strings.HasPrefix(key, contractCodeRegisterKeyPrefix):
+		return RegisterKindContractCode, strings.TrimPrefix(key, contractCodeRegisterKeyPrefix), 0
+	case key == publicKey0RegisterKey:
+		return RegisterKindPublicKey, "", 0
+	case key == accountStorageRegisterKey || storageDomains[key]:
+		return RegisterKindStorageDomain, key, 0
+	case len(key) == storageSlabRegisterKeyLength && key[0] == storageSlabRegisterKeyPrefix:
+		return RegisterKindStorageSlab, "", binary.BigEndian.Uint64([]byte(key[1:]))
+	}
+
+	for prefix, kind := range map[string]RegisterKind{
+		batchPublicKeyRegisterKeyPrefix:  RegisterKindPublicKey,
+		legacyPublicKeyRegisterKeyPrefix: RegisterKindPublicKey,
+		sequenceNumberRegisterKeyPrefix:  RegisterKindSequenceNumber,
+	} {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		index, err := strconv.ParseUint(strings.TrimPrefix(key, prefix), 10, 32)
+		if err != nil {
+			break
+		}
+		return kind, "", index
+	}
+
+	return RegisterKindUnknown, "", 0
+}
+
+// RegisterChanges decodes the register changes of the payloads of the trie update, in order.
+func (u *TrieUpdate) RegisterChanges() ([]RegisterChange, error) {
+	changes := make([]RegisterChange, 0, len(u.Payloads))
+	for i, payload := range u.Payloads {
+		change, err := DecodePayload(payload)
+		if err != nil {
+			return nil, fmt.Errorf("payload %d: %w", i, err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// AccountDiff is the set of registers of an account changed by a block.
+type AccountDiff struct {
+	Address Address
+	// Changes are the final values of the changed registers, by ascending key.
+	Changes []RegisterChange
+}
+
+// AccountDiffs returns the registers of each account changed by the block of the execution
+// data, by ascending address.
+//
+// When the chunks of the block write a register several times, the last value is kept.
+// The registers of the execution environment aren't owned by an account, and are omitted.
+func (d *ExecutionData) AccountDiffs() ([]*AccountDiff, error) {
+	changes := make(map[RegisterID]RegisterChange)
+	for i, chunk := range d.ChunkExecutionData {
+		if chunk.TrieUpdate == nil {
+			continue
+		}
+		chunkChanges, err := chunk.TrieUpdate.RegisterChanges()
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d: %w", i, err)
+		}
+		for _, change := range chunkChanges {
+			if change.Owner == EmptyAddress {
+				continue
+			}
+			changes[change.RegisterID] = change
+		}
+	}
+
+	diffs := make(map[Address]*AccountDiff)
+	for _, change := range changes {
+		diff, ok := diffs[change.Owner]
+		if !ok {
+			diff = &AccountDiff{Address: change.Owner}
+			diffs[change.Owner] = diff
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	sorted := make([]*AccountDiff, 0, len(diffs))
+	for _, diff := range diffs {
+		sort.Slice(diff.Changes, func(i, j int) bool {
+			return diff.Changes[i].Key < diff.Changes[j].Key
+		})
+		sorted = append(sorted, diff)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Address[:], sorted[j].Address[:]) < 0
+	})
+	return sorted, nil
+}
+
+// Sizes of the fields of the account status register.
+const (
+	accountStatusFlagsSize          = 1
+	accountStatusStorageUsedSize    = 8
+	accountStatusStorageIndexSize   = 8
+	accountStatusKeyCountSize       = 4
+	accountStatusLegacyKeyCountSize = 8
+	accountStatusIDCounterSize      = 8
+
+	// legacy account statuses have 8-byte public key counts, and no address ID counter in the
+	// first version
+	accountStatusSizeV1 = accountStatusFlagsSize + accountStatusStorageUsedSize + accountStatusStorageIndexSize + accountStatusLegacyKeyCountSize
+	accountStatusSizeV2 = accountStatusSizeV1 + accountStatusIDCounterSize
+	accountStatusSizeV3 = accountStatusFlagsSize + accountStatusStorageUsedSize + accountStatusStorageIndexSize + accountStatusKeyCountSize + accountStatusIDCounterSize
+)
+
+// AccountStatusRegister is the decoded value of the account status register of an account.
+type AccountStatusRegister struct {
+	// Version is the version of the encoding of the account status.
+	Version uint8
+	// StorageUsed is the storage used by the account, in bytes.
+	StorageUsed uint64
+	// PublicKeyCount is the number of public keys of the account, including revoked keys.
+	PublicKeyCount uint32
+	// AddressIDCounter is the counter of the UUIDs of the account.
+	AddressIDCounter uint64
+}
+
+// DecodeAccountStatusRegister decodes the value of the account status register of an account.
+//
+// The metadata of the public keys following the status in the latest versions isn't decoded.
+func DecodeAccountStatusRegister(value []byte) (AccountStatusRegister, error) {
+	var status AccountStatusRegister
+	if len(value) < accountStatusSizeV1 {
+		return status, fmt.Errorf("invalid account status length %d", len(value))
+	}
+
+	status.Version = value[0] >> 4
+	status.StorageUsed = binary.BigEndian.Uint64(value[accountStatusFlagsSize:])
+	offset := accountStatusFlagsSize + accountStatusStorageUsedSize + accountStatusStorageIndexSize
+
+	switch {
+	case len(value) == accountStatusSizeV1 || len(value) == accountStatusSizeV2:
+		count := binary.BigEndian.Uint64(value[offset:])
+		if count > uint64(^uint32(0)) {
+			return status, fmt.Errorf("invalid public key count %d", count)
+		}
+		status.PublicKeyCount = uint32(count)
+		if len(value) == accountStatusSizeV2 {
+			status.AddressIDCounter = binary.BigEndian.Uint64(value[offset+accountStatusLegacyKeyCountSize:])
+		}
+	case len(value) >= accountStatusSizeV3:
+		status.PublicKeyCount = binary.BigEndian.Uint32(value[offset:])
+		status.AddressIDCounter = binary.BigEndian.Uint64(value[offset+accountStatusKeyCountSize:])
+	default:
+		return status, fmt.Errorf("invalid account status length %d", len(value))
+	}
+
+	return status, nil
+}
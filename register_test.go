@@ -0,0 +1,162 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+func registerPayload(owner flow.Address, key string, value []byte) *flow.Payload {
+	ownerPart := owner.Bytes()
+	if owner == flow.EmptyAddress {
+		ownerPart = nil
+	}
+	return &flow.Payload{
+		KeyPart: []*flow.KeyPart{
+			{Type: flow.KeyPartOwner, Value: ownerPart},
+			{Type: flow.KeyPartKey, Value: []byte(key)},
+		},
+		Value: value,
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	address := flow.HexToAddress("f8d6e0586b0a20c7")
+
+	tests := []struct {
+		owner flow.Address
+		key   string
+		kind  flow.RegisterKind
+		name  string
+		index uint64
+	}{
+		{flow.EmptyAddress, "uuid", flow.RegisterKindGlobal, "", 0},
+		{flow.EmptyAddress, "uuid_3", flow.RegisterKindGlobal, "", 0},
+		{flow.EmptyAddress, "account_address_state", flow.RegisterKindGlobal, "", 0},
+		{address, "a.s", flow.RegisterKindAccountStatus, "", 0},
+		{address, "apk_0", flow.RegisterKindPublicKey, "", 0},
+		{address, "pk_b2", flow.RegisterKindPublicKey, "", 2},
+		{address, "public_key_5", flow.RegisterKindPublicKey, "", 5},
+		{address, "sn_12", flow.RegisterKindSequenceNumber, "", 12},
+		{address, "code.FungibleToken", flow.RegisterKindContractCode, "FungibleToken", 0},
+		{address, "contract_names", flow.RegisterKindContractNames, "", 0},
+		{address, "storage", flow.RegisterKindStorageDomain, "storage", 0},
+		{address, "public", flow.RegisterKindStorageDomain, "public", 0},
+		{address, "stored", flow.RegisterKindStorageDomain, "stored", 0},
+		{address, "$\x00\x00\x00\x00\x00\x00\x01\x02", flow.RegisterKindStorageSlab, "", 258},
+		{address, "sn_x", flow.RegisterKindUnknown, "", 0},
+		{address, "other", flow.RegisterKindUnknown, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			change, err := flow.DecodePayload(registerPayload(tt.owner, tt.key, []byte{1}))
+			require.NoError(t, err)
+			assert.Equal(t, tt.owner, change.Owner)
+			assert.Equal(t, tt.key, change.Key)
+			assert.Equal(t, tt.kind, change.Kind, tt.kind.String())
+			assert.Equal(t, tt.name, change.Name)
+			assert.Equal(t, tt.index, change.Index)
+			assert.Equal(t, []byte{1}, change.Value)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := flow.DecodePayload(&flow.Payload{KeyPart: []*flow.KeyPart{{Type: flow.KeyPartKey, Value: []byte("a.s")}}})
+		assert.Error(t, err)
+
+		_, err = flow.DecodePayload(&flow.Payload{KeyPart: []*flow.KeyPart{{Type: flow.KeyPartOwner, Value: []byte{1, 2}}, {Type: flow.KeyPartKey}}})
+		assert.Error(t, err)
+
+		_, err = flow.DecodePayload(&flow.Payload{KeyPart: []*flow.KeyPart{{Type: 1}}})
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutionData_AccountDiffs(t *testing.T) {
+	alice := flow.HexToAddress("01")
+	bob := flow.HexToAddress("02")
+
+	data := &flow.ExecutionData{
+		ChunkExecutionData: []*flow.ChunkExecutionData{
+			{
+				TrieUpdate: &flow.TrieUpdate{
+					Payloads: []*flow.Payload{
+						registerPayload(bob, "a.s", []byte{1}),
+						registerPayload(flow.EmptyAddress, "uuid", []byte{1}),
+						registerPayload(alice, "storage", []byte{1}),
+					},
+				},
+			},
+			{},
+			{
+				TrieUpdate: &flow.TrieUpdate{
+					Payloads: []*flow.Payload{
+						registerPayload(alice, "a.s", []byte{2}),
+						registerPayload(bob, "a.s", []byte{2}),
+					},
+				},
+			},
+		},
+	}
+
+	diffs, err := data.AccountDiffs()
+	require.NoError(t, err)
+	require.Len(t, diffs, 2)
+
+	assert.Equal(t, alice, diffs[0].Address)
+	require.Len(t, diffs[0].Changes, 2)
+	assert.Equal(t, "a.s", diffs[0].Changes[0].Key)
+	assert.Equal(t, []byte{2}, diffs[0].Changes[0].Value)
+	assert.Equal(t, "storage", diffs[0].Changes[1].Key)
+
+	assert.Equal(t, bob, diffs[1].Address)
+	require.Len(t, diffs[1].Changes, 1)
+	assert.Equal(t, flow.RegisterKindAccountStatus, diffs[1].Changes[0].Kind)
+	assert.Equal(t, []byte{2}, diffs[1].Changes[0].Value)
+}
+
+func TestDecodeAccountStatusRegister(t *testing.T) {
+	// account status encoded by the execution environment
+	value, _ := hex.DecodeString("4000000000000004d20000000000000001000000030000000000000007")
+
+	status, err := flow.DecodeAccountStatusRegister(value)
+	require.NoError(t, err)
+	assert.Equal(t, flow.AccountStatusRegister{
+		Version:          4,
+		StorageUsed:      1234,
+		PublicKeyCount:   3,
+		AddressIDCounter: 7,
+	}, status)
+
+	// legacy account status with an 8-byte public key count
+	value, _ = hex.DecodeString("0000000000000004d2000000000000000100000000000000030000000000000007")
+	status, err = flow.DecodeAccountStatusRegister(value)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), status.PublicKeyCount)
+	assert.Equal(t, uint64(7), status.AddressIDCounter)
+
+	_, err = flow.DecodeAccountStatusRegister(value[:10])
+	assert.Error(t, err)
+}
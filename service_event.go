@@ -0,0 +1,210 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/ccf"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+)
+
+// Service event types, as reported in the Type of the service events of execution results.
+const (
+	ServiceEventEpochSetup                  = "setup"
+	ServiceEventEpochCommit                 = "commit"
+	ServiceEventEpochRecover                = "recover"
+	ServiceEventVersionBeacon               = "version-beacon"
+	ServiceEventProtocolStateVersionUpgrade = "protocol-state-version-upgrade"
+	ServiceEventSetEpochExtensionViewCount  = "set-epoch-extension-view-count"
+	ServiceEventEjectNode                   = "eject-node"
+)
+
+// serviceEventTypesByCadenceType maps the Cadence events emitted by the service contracts,
+// without the address of the contract, to the service event types.
+var serviceEventTypesByCadenceType = map[string]string{
+	"FlowEpoch.EpochSetup":                          ServiceEventEpochSetup,
+	"FlowEpoch.EpochCommit":                         ServiceEventEpochCommit,
+	"FlowEpoch.EpochRecover":                        ServiceEventEpochRecover,
+	"NodeVersionBeacon.VersionBeacon":               ServiceEventVersionBeacon,
+	"NodeVersionBeacon.ProtocolStateVersionUpgrade": ServiceEventProtocolStateVersionUpgrade,
+}
+
+// ErrUnknownServiceEvent is returned when decoding a service event of an unsupported type.
+var ErrUnknownServiceEvent = errors.New("unknown service event type")
+
+// VersionBoundary is the minimum version of the execution software required from a block height.
+type VersionBoundary struct {
+	BlockHeight uint64
+	// Version is a semantic version, such as "0.33.2" or "0.34.0-rc.1".
+	Version string
+}
+
+// VersionBeacon is the service event emitted when the version boundaries of the execution
+// software are updated.
+type VersionBeacon struct {
+	// VersionBoundaries are the boundaries by ascending block height.
+	VersionBoundaries []VersionBoundary
+	// Sequence is incremented for each version beacon emitted.
+	Sequence uint64
+}
+
+// ProtocolStateVersionUpgrade is the service event emitted to schedule an upgrade of the
+// version of the protocol state.
+type ProtocolStateVersionUpgrade struct {
+	NewProtocolStateVersion uint64
+	// ActiveView is the first view at which the new version is active.
+	ActiveView uint64
+}
+
+// EpochRecover is the service event emitted to recover the network from epoch fallback mode,
+// setting up and committing the next epoch at once.
+type EpochRecover struct {
+	EpochSetup  EpochSetup
+	EpochCommit EpochCommit
+}
+
+// SetEpochExtensionViewCount is the service event emitted to set the number of views by which
+// the current epoch is extended in epoch fallback mode.
+type SetEpochExtensionViewCount struct {
+	Value uint64
+}
+
+// EjectNode is the service event emitted when a node is ejected from the network.
+type EjectNode struct {
+	NodeID Identifier
+}
+
+// Decode decodes the payload of the service event into its typed representation, one of
+// *EpochSetup, *EpochCommit, *EpochRecover, *VersionBeacon, *ProtocolStateVersionUpgrade,
+// *SetEpochExtensionViewCount or *EjectNode.
+//
+// The type of the service event is either a service event type, such as "setup", or the type of
+// the Cadence event emitted by the service contract, such as "A.8624b52f9ddcd04a.FlowEpoch.EpochSetup".
+// Payloads in the JSON encoding of the protocol, as returned in execution results by the Access API,
+// and Cadence event payloads in the CCF or JSON-CDC encodings are supported.
+// The error returned for service events of other types wraps ErrUnknownServiceEvent.
+func (s ServiceEvent) Decode() (interface{}, error) {
+	eventType, isCadenceType := serviceEventType(s.Type)
+
+	if isCadenceType || ccf.HasMsgPrefix(s.Payload) {
+		value, err := decodeCadenceServiceEvent(s.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s service event: %w", s.Type, err)
+		}
+
+		var event interface{}
+		switch eventType {
+		case ServiceEventEpochSetup:
+			event, err = cadenceToEpochSetup(value)
+		case ServiceEventEpochCommit:
+			event, err = cadenceToEpochCommit(value)
+		case ServiceEventEpochRecover:
+			event, err = cadenceToEpochRecover(value)
+		case ServiceEventVersionBeacon:
+			event, err = cadenceToVersionBeacon(value)
+		case ServiceEventProtocolStateVersionUpgrade:
+			event, err = cadenceToProtocolStateVersionUpgrade(value)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownServiceEvent, s.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s service event: %w", s.Type, err)
+		}
+		return event, nil
+	}
+
+	var event interface{}
+	switch eventType {
+	case ServiceEventEpochSetup:
+		event = new(EpochSetup)
+	case ServiceEventEpochCommit:
+		event = new(EpochCommit)
+	case ServiceEventEpochRecover:
+		event = new(EpochRecover)
+	case ServiceEventVersionBeacon:
+		event = new(VersionBeacon)
+	case ServiceEventProtocolStateVersionUpgrade:
+		event = new(ProtocolStateVersionUpgrade)
+	case ServiceEventSetEpochExtensionViewCount:
+		event = new(SetEpochExtensionViewCount)
+	case ServiceEventEjectNode:
+		event = new(EjectNode)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownServiceEvent, s.Type)
+	}
+	if err := json.Unmarshal(s.Payload, event); err != nil {
+		return nil, fmt.Errorf("failed to decode %s service event: %w", s.Type, err)
+	}
+	return event, nil
+}
+
+// DecodeServiceEvents decodes the service events of the execution result, in order.
+//
+// See ServiceEvent.Decode for the supported types and encodings.
+func (r ExecutionResult) DecodeServiceEvents() ([]interface{}, error) {
+	events := make([]interface{}, 0, len(r.ServiceEvents))
+	for i, serviceEvent := range r.ServiceEvents {
+		event, err := serviceEvent.Decode()
+		if err != nil {
+			return nil, fmt.Errorf("service event %d: %w", i, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// serviceEventType returns the service event type of the given type, and whether it is the
+// type of a Cadence event.
+func serviceEventType(t string) (string, bool) {
+	// Cadence event types are in the form A.<address>.<contract>.<event>
+	parts := strings.Split(t, ".")
+	if len(parts) != 4 || parts[0] != "A" {
+		return t, false
+	}
+	eventType, ok := serviceEventTypesByCadenceType[parts[2]+"."+parts[3]]
+	if !ok {
+		return t, true
+	}
+	return eventType, true
+}
+
+func decodeCadenceServiceEvent(payload []byte) (cadence.Event, error) {
+	var (
+		value cadence.Value
+		err   error
+	)
+	if ccf.HasMsgPrefix(payload) {
+		value, err = ccf.Decode(nil, payload)
+	} else {
+		value, err = jsoncdc.Decode(nil, payload)
+	}
+	if err != nil {
+		return cadence.Event{}, err
+	}
+
+	event, ok := value.(cadence.Event)
+	if !ok {
+		return cadence.Event{}, fmt.Errorf("payload is not an event")
+	}
+	return event, nil
+}
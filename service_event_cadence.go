@@ -0,0 +1,528 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence"
+	onflowcrypto "github.com/onflow/crypto"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// cadenceRoles maps the roles of the staking contract to the node roles.
+var cadenceRoles = map[uint8]Role{
+	1: RoleCollection,
+	2: RoleConsensus,
+	3: RoleExecution,
+	4: RoleVerification,
+	5: RoleAccess,
+}
+
+// cadenceField returns the field of a composite value with the given name and type.
+func cadenceField[T cadence.Value](fields map[string]cadence.Value, name string) (T, error) {
+	var zero T
+	field, ok := fields[name]
+	if !ok || field == nil {
+		return zero, fmt.Errorf("missing field %s", name)
+	}
+	value, ok := field.(T)
+	if !ok {
+		return zero, fmt.Errorf("invalid field %s: expected %T, got %T", name, zero, field)
+	}
+	return value, nil
+}
+
+func cadenceToEpochSetup(event cadence.Event) (*EpochSetup, error) {
+	fields := cadence.FieldsMappedByName(event)
+
+	clusters, err := cadenceField[cadence.Array](fields, "collectorClusters")
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := cadenceToClusterAssignments(clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	return cadenceToEpochSetupWithAssignments(fields, assignments)
+}
+
+// cadenceToEpochSetupWithAssignments decodes the fields shared by the epoch setup and epoch recover events.
+func cadenceToEpochSetupWithAssignments(fields map[string]cadence.Value, assignments [][]Identifier) (*EpochSetup, error) {
+	setup := &EpochSetup{Assignments: assignments}
+
+	for name, field := range map[string]*uint64{
+		"counter":            &setup.Counter,
+		"firstView":          &setup.FirstView,
+		"finalView":          &setup.FinalView,
+		"DKGPhase1FinalView": &setup.DKGPhase1FinalView,
+		"DKGPhase2FinalView": &setup.DKGPhase2FinalView,
+		"DKGPhase3FinalView": &setup.DKGPhase3FinalView,
+		"targetDuration":     &setup.TargetDuration,
+		"targetEndTime":      &setup.TargetEndTime,
+	} {
+		value, err := cadenceField[cadence.UInt64](fields, name)
+		if err != nil {
+			return nil, err
+		}
+		*field = uint64(value)
+	}
+
+	randomSource, err := cadenceField[cadence.String](fields, "randomSource")
+	if err != nil {
+		return nil, err
+	}
+	setup.RandomSource, err = hex.DecodeString(string(randomSource))
+	if err != nil {
+		return nil, fmt.Errorf("invalid random source: %w", err)
+	}
+
+	nodes, err := cadenceField[cadence.Array](fields, "nodeInfo")
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range nodes.Values {
+		identity, err := cadenceToIdentity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node %d: %w", i, err)
+		}
+		setup.Participants = append(setup.Participants, identity)
+	}
+	sort.Slice(setup.Participants, func(i, j int) bool {
+		return bytes.Compare(setup.Participants[i].NodeID[:], setup.Participants[j].NodeID[:]) < 0
+	})
+
+	return setup, nil
+}
+
+// cadenceToIdentity decodes the node info of the staking contract.
+func cadenceToIdentity(value cadence.Value) (*Identity, error) {
+	node, ok := value.(cadence.Struct)
+	if !ok {
+		return nil, fmt.Errorf("expected struct, got %T", value)
+	}
+	fields := cadence.FieldsMappedByName(node)
+
+	id, err := cadenceField[cadence.String](fields, "id")
+	if err != nil {
+		return nil, err
+	}
+	role, err := cadenceField[cadence.UInt8](fields, "role")
+	if err != nil {
+		return nil, err
+	}
+	address, err := cadenceField[cadence.String](fields, "networkingAddress")
+	if err != nil {
+		return nil, err
+	}
+	networkKey, err := cadenceField[cadence.String](fields, "networkingKey")
+	if err != nil {
+		return nil, err
+	}
+	stakingKey, err := cadenceField[cadence.String](fields, "stakingKey")
+	if err != nil {
+		return nil, err
+	}
+	weight, err := cadenceField[cadence.UInt64](fields, "initialWeight")
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Address:       string(address),
+		InitialWeight: uint64(weight),
+	}
+	if identity.NodeID, err = cadenceToIdentifier(id); err != nil {
+		return nil, err
+	}
+	if identity.Role, ok = cadenceRoles[uint8(role)]; !ok {
+		return nil, fmt.Errorf("invalid role %d of node %s", role, identity.NodeID)
+	}
+	identity.NetworkKey, err = crypto.DecodePublicKeyHex(crypto.ECDSA_P256, string(networkKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid network key of node %s: %w", identity.NodeID, err)
+	}
+	identity.StakingKey, err = crypto.DecodePublicKeyHex(crypto.BLS_BLS12_381, string(stakingKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid staking key of node %s: %w", identity.NodeID, err)
+	}
+	return identity, nil
+}
+
+// cadenceToClusterAssignments decodes the clusters of the epoch setup event, indexed by the
+// weights of their nodes.
+func cadenceToClusterAssignments(clusters cadence.Array) ([][]Identifier, error) {
+	assignments := make([][]Identifier, len(clusters.Values))
+	for _, value := range clusters.Values {
+		cluster, ok := value.(cadence.Struct)
+		if !ok {
+			return nil, fmt.Errorf("expected cluster struct, got %T", value)
+		}
+		fields := cadence.FieldsMappedByName(cluster)
+
+		index, err := cadenceField[cadence.UInt16](fields, "index")
+		if err != nil {
+			return nil, err
+		}
+		if int(index) >= len(assignments) || assignments[index] != nil {
+			return nil, fmt.Errorf("invalid cluster index %d", index)
+		}
+		weights, err := cadenceField[cadence.Dictionary](fields, "nodeWeights")
+		if err != nil {
+			return nil, err
+		}
+
+		members := make([]Identifier, 0, len(weights.Pairs))
+		for _, pair := range weights.Pairs {
+			nodeID, err := cadenceToIdentifier(pair.Key)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, nodeID)
+		}
+		assignments[index] = sortIdentifiers(members)
+	}
+	return assignments, nil
+}
+
+func cadenceToEpochCommit(event cadence.Event) (*EpochCommit, error) {
+	fields := cadence.FieldsMappedByName(event)
+
+	qcs, err := cadenceField[cadence.Array](fields, "clusterQCs")
+	if err != nil {
+		return nil, err
+	}
+	commit, err := cadenceToEpochCommitWithoutQCs(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// the votes of each cluster are aggregated into the signature of its root quorum certificate
+	commit.ClusterQCs = make([]ClusterQCVoteData, len(qcs.Values))
+	indices := make(map[uint16]bool, len(qcs.Values))
+	for _, value := range qcs.Values {
+		qc, ok := value.(cadence.Struct)
+		if !ok {
+			return nil, fmt.Errorf("expected cluster QC struct, got %T", value)
+		}
+		fields := cadence.FieldsMappedByName(qc)
+
+		index, err := cadenceField[cadence.UInt16](fields, "index")
+		if err != nil {
+			return nil, err
+		}
+		if int(index) >= len(commit.ClusterQCs) || indices[uint16(index)] {
+			return nil, fmt.Errorf("invalid cluster QC index %d", index)
+		}
+		indices[uint16(index)] = true
+
+		votes, err := cadenceField[cadence.Array](fields, "voteSignatures")
+		if err != nil {
+			return nil, err
+		}
+		signatures := make([]onflowcrypto.Signature, 0, len(votes.Values))
+		for _, vote := range votes.Values {
+			signature, err := cadenceToHex(vote)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vote of cluster %d: %w", index, err)
+			}
+			signatures = append(signatures, signature)
+		}
+		aggregated, err := onflowcrypto.AggregateBLSSignatures(signatures)
+		if err != nil {
+			return nil, fmt.Errorf("failed to aggregate votes of cluster %d: %w", index, err)
+		}
+
+		voterIDs, err := cadenceToVoterIDs(fields)
+		if err != nil {
+			return nil, err
+		}
+		commit.ClusterQCs[index] = ClusterQCVoteData{
+			SigData:  aggregated,
+			VoterIDs: voterIDs,
+		}
+	}
+
+	return commit, nil
+}
+
+// cadenceToEpochCommitWithoutQCs decodes the fields shared by the epoch commit and epoch recover events.
+func cadenceToEpochCommitWithoutQCs(fields map[string]cadence.Value) (*EpochCommit, error) {
+	counter, err := cadenceField[cadence.UInt64](fields, "counter")
+	if err != nil {
+		return nil, err
+	}
+	commit := &EpochCommit{Counter: uint64(counter)}
+
+	groupKey, err := cadenceField[cadence.String](fields, "dkgGroupKey")
+	if err != nil {
+		return nil, err
+	}
+	commit.DKGGroupKey, err = crypto.DecodePublicKeyHex(crypto.BLS_BLS12_381, string(groupKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DKG group key: %w", err)
+	}
+
+	keys, err := cadenceField[cadence.Array](fields, "dkgPubKeys")
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range keys.Values {
+		encoded, ok := value.(cadence.String)
+		if !ok {
+			return nil, fmt.Errorf("expected DKG key string, got %T", value)
+		}
+		key, err := crypto.DecodePublicKeyHex(crypto.BLS_BLS12_381, string(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid DKG key of participant %d: %w", i, err)
+		}
+		commit.DKGParticipantKeys = append(commit.DKGParticipantKeys, key)
+	}
+
+	indexMap, err := cadenceField[cadence.Dictionary](fields, "dkgIdMapping")
+	if err != nil {
+		return nil, err
+	}
+	commit.DKGIndexMap = make(map[Identifier]int, len(indexMap.Pairs))
+	for _, pair := range indexMap.Pairs {
+		nodeID, err := cadenceToIdentifier(pair.Key)
+		if err != nil {
+			return nil, err
+		}
+		index, ok := pair.Value.(cadence.Int)
+		if !ok {
+			return nil, fmt.Errorf("expected DKG index, got %T", pair.Value)
+		}
+		commit.DKGIndexMap[nodeID] = index.Int()
+	}
+
+	return commit, nil
+}
+
+func cadenceToEpochRecover(event cadence.Event) (*EpochRecover, error) {
+	fields := cadence.FieldsMappedByName(event)
+
+	// unlike in the epoch setup event, the clusters are the lists of their node IDs
+	clusters, err := cadenceField[cadence.Array](fields, "clusterAssignments")
+	if err != nil {
+		return nil, err
+	}
+	assignments := make([][]Identifier, 0, len(clusters.Values))
+	for _, value := range clusters.Values {
+		cluster, ok := value.(cadence.Array)
+		if !ok {
+			return nil, fmt.Errorf("expected cluster array, got %T", value)
+		}
+		members := make([]Identifier, 0, len(cluster.Values))
+		for _, member := range cluster.Values {
+			nodeID, err := cadenceToIdentifier(member)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, nodeID)
+		}
+		assignments = append(assignments, sortIdentifiers(members))
+	}
+
+	setup, err := cadenceToEpochSetupWithAssignments(fields, assignments)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := cadenceToEpochCommitWithoutQCs(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// unlike in the epoch commit event, the root quorum certificates of the clusters are aggregated
+	qcs, err := cadenceField[cadence.Array](fields, "clusterQCVoteData")
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range qcs.Values {
+		qc, ok := value.(cadence.Struct)
+		if !ok {
+			return nil, fmt.Errorf("expected cluster QC struct, got %T", value)
+		}
+		fields := cadence.FieldsMappedByName(qc)
+
+		signature, err := cadenceField[cadence.String](fields, "aggregatedSignature")
+		if err != nil {
+			return nil, err
+		}
+		sigData, err := cadenceToHex(signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature of cluster %d: %w", i, err)
+		}
+		voterIDs, err := cadenceToVoterIDs(fields)
+		if err != nil {
+			return nil, err
+		}
+		commit.ClusterQCs = append(commit.ClusterQCs, ClusterQCVoteData{
+			SigData:  sigData,
+			VoterIDs: voterIDs,
+		})
+	}
+
+	return &EpochRecover{
+		EpochSetup:  *setup,
+		EpochCommit: *commit,
+	}, nil
+}
+
+func cadenceToVersionBeacon(event cadence.Event) (*VersionBeacon, error) {
+	fields := cadence.FieldsMappedByName(event)
+
+	sequence, err := cadenceField[cadence.UInt64](fields, "sequence")
+	if err != nil {
+		return nil, err
+	}
+	boundaries, err := cadenceField[cadence.Array](fields, "versionBoundaries")
+	if err != nil {
+		return nil, err
+	}
+
+	beacon := &VersionBeacon{
+		VersionBoundaries: make([]VersionBoundary, 0, len(boundaries.Values)),
+		Sequence:          uint64(sequence),
+	}
+	for i, value := range boundaries.Values {
+		boundary, ok := value.(cadence.Struct)
+		if !ok {
+			return nil, fmt.Errorf("expected version boundary struct, got %T", value)
+		}
+		fields := cadence.FieldsMappedByName(boundary)
+
+		height, err := cadenceField[cadence.UInt64](fields, "blockHeight")
+		if err != nil {
+			return nil, fmt.Errorf("invalid version boundary %d: %w", i, err)
+		}
+		semver, err := cadenceField[cadence.Struct](fields, "version")
+		if err != nil {
+			return nil, fmt.Errorf("invalid version boundary %d: %w", i, err)
+		}
+		version, err := cadenceToSemver(semver)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version boundary %d: %w", i, err)
+		}
+
+		beacon.VersionBoundaries = append(beacon.VersionBoundaries, VersionBoundary{
+			BlockHeight: uint64(height),
+			Version:     version,
+		})
+	}
+
+	return beacon, nil
+}
+
+// cadenceToSemver formats the semantic version struct of the version beacon contract.
+func cadenceToSemver(semver cadence.Struct) (string, error) {
+	fields := cadence.FieldsMappedByName(semver)
+
+	var numbers [3]cadence.UInt8
+	for i, name := range []string{"major", "minor", "patch"} {
+		number, err := cadenceField[cadence.UInt8](fields, name)
+		if err != nil {
+			return "", err
+		}
+		numbers[i] = number
+	}
+	version := fmt.Sprintf("%d.%d.%d", numbers[0], numbers[1], numbers[2])
+
+	preRelease, err := cadenceField[cadence.Optional](fields, "preRelease")
+	if err != nil {
+		return "", err
+	}
+	if preRelease.Value != nil {
+		label, ok := preRelease.Value.(cadence.String)
+		if !ok {
+			return "", fmt.Errorf("expected pre-release string, got %T", preRelease.Value)
+		}
+		if label != "" {
+			version += "-" + string(label)
+		}
+	}
+	return version, nil
+}
+
+func cadenceToProtocolStateVersionUpgrade(event cadence.Event) (*ProtocolStateVersionUpgrade, error) {
+	fields := cadence.FieldsMappedByName(event)
+
+	version, err := cadenceField[cadence.UInt64](fields, "newProtocolVersion")
+	if err != nil {
+		return nil, err
+	}
+	activeView, err := cadenceField[cadence.UInt64](fields, "activeView")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProtocolStateVersionUpgrade{
+		NewProtocolStateVersion: uint64(version),
+		ActiveView:              uint64(activeView),
+	}, nil
+}
+
+func cadenceToVoterIDs(fields map[string]cadence.Value) ([]Identifier, error) {
+	voters, err := cadenceField[cadence.Array](fields, "voterIDs")
+	if err != nil {
+		return nil, err
+	}
+	voterIDs := make([]Identifier, 0, len(voters.Values))
+	for _, value := range voters.Values {
+		voterID, err := cadenceToIdentifier(value)
+		if err != nil {
+			return nil, err
+		}
+		voterIDs = append(voterIDs, voterID)
+	}
+	return voterIDs, nil
+}
+
+// cadenceToIdentifier decodes a node ID, encoded as a hex string by the service contracts.
+func cadenceToIdentifier(value cadence.Value) (Identifier, error) {
+	var id Identifier
+	encoded, ok := value.(cadence.String)
+	if !ok {
+		return id, fmt.Errorf("expected node ID string, got %T", value)
+	}
+	if err := id.UnmarshalText([]byte(encoded)); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+func cadenceToHex(value cadence.Value) ([]byte, error) {
+	encoded, ok := value.(cadence.String)
+	if !ok {
+		return nil, fmt.Errorf("expected hex string, got %T", value)
+	}
+	return hex.DecodeString(string(encoded))
+}
+
+// sortIdentifiers sorts the identifiers in canonical order.
+func sortIdentifiers(ids []Identifier) []Identifier {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+	return ids
+}
@@ -0,0 +1,176 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/onflow/cadence/encoding/ccf"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// serviceEventVector is a service event in the JSON encoding of the protocol, and when emitted by
+// a service contract, the Cadence event in the CCF encoding.
+type serviceEventVector struct {
+	Type      string
+	EventType string
+	CCF       []byte
+	JSON      json.RawMessage
+}
+
+func loadServiceEventVectors(t *testing.T) []serviceEventVector {
+	data, err := os.ReadFile("testdata/service_events.json")
+	require.NoError(t, err)
+
+	var vectors []serviceEventVector
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	return vectors
+}
+
+func TestServiceEvent_Decode(t *testing.T) {
+	vectors := loadServiceEventVectors(t)
+	decoded := make(map[string]interface{})
+	for _, vector := range vectors {
+		event, err := flow.ServiceEvent{Type: vector.Type, Payload: vector.JSON}.Decode()
+		require.NoError(t, err, vector.Type)
+		decoded[vector.Type] = event
+	}
+
+	t.Run("Epoch setup", func(t *testing.T) {
+		setup, ok := decoded[flow.ServiceEventEpochSetup].(*flow.EpochSetup)
+		require.True(t, ok)
+		assert.Equal(t, uint64(1), setup.Counter)
+		assert.Equal(t, uint64(100), setup.FirstView)
+		assert.Equal(t, uint64(200), setup.FinalView)
+		assert.Len(t, setup.RandomSource, 16)
+		require.Len(t, setup.Assignments, 2)
+		assert.Equal(t, []flow.Identifier{flow.HexToID("0000000000000000000000000000000000000000000000000000000000000003"), flow.HexToID("0000000000000000000000000000000000000000000000000000000000000004")}, setup.Assignments[1])
+		assert.Equal(t, flow.RoleCollection, setup.Participants[0].Role)
+		assert.Equal(t, "1.flow.com", setup.Participants[0].Address)
+	})
+
+	t.Run("Epoch commit", func(t *testing.T) {
+		commit, ok := decoded[flow.ServiceEventEpochCommit].(*flow.EpochCommit)
+		require.True(t, ok)
+		assert.Equal(t, uint64(1), commit.Counter)
+		assert.Len(t, commit.ClusterQCs, 2)
+		assert.Len(t, commit.DKGParticipantKeys, 1)
+		assert.Len(t, commit.DKGIndexMap, 1)
+	})
+
+	t.Run("Epoch recover", func(t *testing.T) {
+		epochRecover, ok := decoded[flow.ServiceEventEpochRecover].(*flow.EpochRecover)
+		require.True(t, ok)
+		assert.Equal(t, uint64(1), epochRecover.EpochSetup.Counter)
+		assert.Equal(t, uint64(1), epochRecover.EpochCommit.Counter)
+	})
+
+	t.Run("Version beacon", func(t *testing.T) {
+		assert.Equal(t, &flow.VersionBeacon{
+			VersionBoundaries: []flow.VersionBoundary{{BlockHeight: 44, Version: "2.13.7-test"}},
+			Sequence:          5,
+		}, decoded[flow.ServiceEventVersionBeacon])
+	})
+
+	t.Run("Protocol state version upgrade", func(t *testing.T) {
+		assert.Equal(t, &flow.ProtocolStateVersionUpgrade{
+			NewProtocolStateVersion: 1,
+			ActiveView:              1000,
+		}, decoded[flow.ServiceEventProtocolStateVersionUpgrade])
+	})
+
+	t.Run("Set epoch extension view count", func(t *testing.T) {
+		assert.Equal(t, &flow.SetEpochExtensionViewCount{Value: 12000}, decoded[flow.ServiceEventSetEpochExtensionViewCount])
+	})
+
+	t.Run("Eject node", func(t *testing.T) {
+		assert.Equal(t, &flow.EjectNode{NodeID: flow.HexToID("0000000000000000000000000000000000000000000000000000000000000021")}, decoded[flow.ServiceEventEjectNode])
+	})
+}
+
+func TestServiceEvent_Decode_Cadence(t *testing.T) {
+	for _, vector := range loadServiceEventVectors(t) {
+		if vector.CCF == nil {
+			continue
+		}
+
+		t.Run(vector.Type, func(t *testing.T) {
+			expected, err := flow.ServiceEvent{Type: vector.Type, Payload: vector.JSON}.Decode()
+			require.NoError(t, err)
+			expectedJSON, err := json.Marshal(expected)
+			require.NoError(t, err)
+
+			value, err := ccf.Decode(nil, vector.CCF)
+			require.NoError(t, err)
+			jsonCDC, err := jsoncdc.Encode(value)
+			require.NoError(t, err)
+
+			for name, serviceEvent := range map[string]flow.ServiceEvent{
+				"CCF":                 {Type: vector.EventType, Payload: vector.CCF},
+				"CCF with event type": {Type: vector.Type, Payload: vector.CCF},
+				"JSON-CDC":            {Type: vector.EventType, Payload: jsonCDC},
+			} {
+				event, err := serviceEvent.Decode()
+				require.NoError(t, err, name)
+				assert.IsType(t, expected, event, name)
+
+				// keys are compared by their encoding
+				eventJSON, err := json.Marshal(event)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(expectedJSON), string(eventJSON), name)
+			}
+		})
+	}
+}
+
+func TestServiceEvent_Decode_Invalid(t *testing.T) {
+	_, err := flow.ServiceEvent{Type: "unknown", Payload: []byte("{}")}.Decode()
+	assert.ErrorIs(t, err, flow.ErrUnknownServiceEvent)
+
+	_, err = flow.ServiceEvent{Type: "A.0000000000000001.Test.Event", Payload: []byte("{}")}.Decode()
+	assert.Error(t, err)
+
+	_, err = flow.ServiceEvent{Type: flow.ServiceEventEpochSetup, Payload: []byte("{")}.Decode()
+	assert.Error(t, err)
+}
+
+func TestExecutionResult_DecodeServiceEvents(t *testing.T) {
+	result := flow.ExecutionResult{
+		ServiceEvents: []*flow.ServiceEvent{
+			{Type: flow.ServiceEventSetEpochExtensionViewCount, Payload: []byte(`{"Value":100}`)},
+			{Type: flow.ServiceEventProtocolStateVersionUpgrade, Payload: []byte(`{"NewProtocolStateVersion":2,"ActiveView":50}`)},
+		},
+	}
+
+	events, err := result.DecodeServiceEvents()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		&flow.SetEpochExtensionViewCount{Value: 100},
+		&flow.ProtocolStateVersionUpgrade{NewProtocolStateVersion: 2, ActiveView: 50},
+	}, events)
+
+	result.ServiceEvents = append(result.ServiceEvents, &flow.ServiceEvent{Type: "unknown"})
+	_, err = result.DecodeServiceEvents()
+	assert.ErrorIs(t, err, flow.ErrUnknownServiceEvent)
+}
@@ -19,6 +19,7 @@
 package flow
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/onflow/flow-go-sdk/crypto"
@@ -62,6 +63,127 @@ func padDomainTag(tag string) (paddedTag [domainTagLength]byte, err error) {
 // User messages are distinct from other signed messages (i.e. transactions), and can be
 // verified directly in on-chain Cadence code.
 func SignUserMessage(signer crypto.Signer, message []byte) ([]byte, error) {
+	return SignUserMessageContext(context.Background(), signer, message)
+}
+
+// SignUserMessageContext signs a message in the user domain, using the given context
+// for the signing request.
+//
+// Signers that do not implement crypto.ContextSigner are adapted with crypto.NewContextSigner.
+func SignUserMessageContext(ctx context.Context, signer crypto.Signer, message []byte) ([]byte, error) {
 	message = append(UserDomainTag[:], message...)
-	return signer.Sign(message)
+	return crypto.NewContextSigner(signer).SignContext(ctx, message)
+}
+
+// CompositeSignature is a user message signature with the account key that produced it.
+type CompositeSignature struct {
+	Address   Address
+	KeyIndex  uint32
+	Signature []byte
+}
+
+// UserSignatureValidation is the way a set of user message signatures is validated.
+type UserSignatureValidation int
+
+const (
+	// ValidateAll requires all the signatures to be valid, and the total weight of the distinct keys
+	// of each account to reach AccountKeyWeightThreshold.
+	ValidateAll UserSignatureValidation = iota
+	// ValidateAny requires at least one valid signature, whatever the weight of its key.
+	ValidateAny
+)
+
+// UserSignatureClient is the subset of the Access API client used to verify user signatures online.
+//
+// It is implemented by the gRPC and HTTP access clients.
+type UserSignatureClient interface {
+	GetAccountKeysAtBlockHeight(ctx context.Context, address Address, height uint64) ([]*AccountKey, error)
+}
+
+// VerifyUserSignature verifies a signature of a message in the user domain, produced by the
+// account key at the signature key index.
+//
+// The keys are the keys of the signing account. The signature is invalid if the key doesn't exist
+// or is revoked. The weight of the key is not checked.
+func VerifyUserSignature(message []byte, signature CompositeSignature, keys []*AccountKey) (bool, error) {
+	return VerifyUserSignatures(message, []CompositeSignature{signature}, keys, ValidateAny)
+}
+
+// VerifyUserSignatures verifies signatures of a message in the user domain, produced by keys of
+// the same account.
+//
+// The keys are the keys of the signing account. Signatures produced by missing or revoked keys are
+// invalid. An error is returned if the signatures are from several accounts.
+func VerifyUserSignatures(
+	message []byte,
+	signatures []CompositeSignature,
+	keys []*AccountKey,
+	validation UserSignatureValidation,
+) (bool, error) {
+	if len(signatures) == 0 {
+		return false, nil
+	}
+
+	address := signatures[0].Address
+	for _, signature := range signatures[1:] {
+		if signature.Address != address {
+			return false, fmt.Errorf("signatures from several accounts: %s and %s", address, signature.Address)
+		}
+	}
+
+	return verifyUserSignatures(message, signatures, map[Address][]*AccountKey{address: keys}, validation)
+}
+
+// VerifyUserSignaturesAtBlockHeight verifies signatures of a message in the user domain,
+// using the account keys at the given block height.
+//
+// Signatures can be from several accounts. With ValidateAll, the keys of each account must reach
+// the weight threshold.
+func VerifyUserSignaturesAtBlockHeight(
+	ctx context.Context,
+	client UserSignatureClient,
+	height uint64,
+	message []byte,
+	signatures []CompositeSignature,
+	validation UserSignatureValidation,
+) (bool, error) {
+	keys := make(map[Address][]*AccountKey)
+	for _, signature := range signatures {
+		if _, ok := keys[signature.Address]; ok {
+			continue
+		}
+
+		accountKeys, err := client.GetAccountKeysAtBlockHeight(ctx, signature.Address, height)
+		if err != nil {
+			return false, fmt.Errorf("failed to get keys of account %s at height %d: %w", signature.Address, height, err)
+		}
+		keys[signature.Address] = accountKeys
+	}
+
+	return verifyUserSignatures(message, signatures, keys, validation)
+}
+
+func verifyUserSignatures(
+	message []byte,
+	signatures []CompositeSignature,
+	keys map[Address][]*AccountKey,
+	validation UserSignatureValidation,
+) (bool, error) {
+	if validation != ValidateAll && validation != ValidateAny {
+		return false, fmt.Errorf("unsupported user signature validation %d", validation)
+	}
+	if len(signatures) == 0 {
+		return false, nil
+	}
+
+	results, _, valid := verifyWeightedSignatures(message, signatures, keys)
+	if validation == ValidateAll {
+		return valid, nil
+	}
+	for _, result := range results {
+		if result.Valid {
+			return true, nil
+		}
+	}
+	return false, nil
 }
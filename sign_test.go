@@ -0,0 +1,204 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flow_test
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// testAccount is an account with a signer for each of its keys.
+type testAccount struct {
+	address flow.Address
+	keys    []*flow.AccountKey
+	signers []crypto.Signer
+}
+
+func newTestAccount(t *testing.T, address flow.Address, weights ...int) testAccount {
+	account := testAccount{address: address}
+	for i, weight := range weights {
+		seed := make([]byte, crypto.MinSeedLength)
+		_, err := rand.Read(seed)
+		require.NoError(t, err)
+		sk, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, seed)
+		require.NoError(t, err)
+		signer, err := crypto.NewInMemorySigner(sk, crypto.SHA3_256)
+		require.NoError(t, err)
+
+		account.signers = append(account.signers, signer)
+		account.keys = append(account.keys, &flow.AccountKey{
+			Index:     uint32(i),
+			PublicKey: sk.PublicKey(),
+			SigAlgo:   crypto.ECDSA_P256,
+			HashAlgo:  crypto.SHA3_256,
+			Weight:    weight,
+		})
+	}
+	return account
+}
+
+func (a testAccount) sign(t *testing.T, keyIndex uint32, message []byte) flow.CompositeSignature {
+	signature, err := flow.SignUserMessage(a.signers[keyIndex], message)
+	require.NoError(t, err)
+	return flow.CompositeSignature{Address: a.address, KeyIndex: keyIndex, Signature: signature}
+}
+
+// keysAtHeightClient serves the keys of a set of accounts, at any height.
+type keysAtHeightClient map[flow.Address][]*flow.AccountKey
+
+func (c keysAtHeightClient) GetAccountKeysAtBlockHeight(_ context.Context, address flow.Address, _ uint64) ([]*flow.AccountKey, error) {
+	keys, ok := c[address]
+	if !ok {
+		return nil, fmt.Errorf("account %s not found", address)
+	}
+	return keys, nil
+}
+
+func TestVerifyUserSignatures(t *testing.T) {
+	message := []byte("ananas")
+	alice := newTestAccount(t, flow.HexToAddress("01"), 500, 500, 1000)
+	alice.keys[2].Revoked = true
+
+	t.Run("Single signature", func(t *testing.T) {
+		valid, err := flow.VerifyUserSignature(message, alice.sign(t, 0, message), alice.keys)
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		// revoked key
+		valid, err = flow.VerifyUserSignature(message, alice.sign(t, 2, message), alice.keys)
+		require.NoError(t, err)
+		assert.False(t, valid)
+
+		// other message
+		valid, err = flow.VerifyUserSignature([]byte("banana"), alice.sign(t, 0, message), alice.keys)
+		require.NoError(t, err)
+		assert.False(t, valid)
+
+		// signature without the user domain tag
+		signature, err := alice.signers[0].Sign(message)
+		require.NoError(t, err)
+		valid, err = flow.VerifyUserSignature(message, flow.CompositeSignature{Address: alice.address, Signature: signature}, alice.keys)
+		require.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("Validate all", func(t *testing.T) {
+		valid, err := flow.VerifyUserSignatures(message, []flow.CompositeSignature{
+			alice.sign(t, 1, message),
+			alice.sign(t, 0, message),
+		}, alice.keys, flow.ValidateAll)
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		// a key weight is counted once
+		valid, err = flow.VerifyUserSignatures(message, []flow.CompositeSignature{
+			alice.sign(t, 0, message),
+			alice.sign(t, 0, message),
+		}, alice.keys, flow.ValidateAll)
+		require.NoError(t, err)
+		assert.False(t, valid)
+
+		// all the signatures must be valid
+		invalid := alice.sign(t, 0, message)
+		invalid.KeyIndex = 1
+		valid, err = flow.VerifyUserSignatures(message, []flow.CompositeSignature{
+			alice.sign(t, 0, message),
+			alice.sign(t, 1, message),
+			invalid,
+		}, alice.keys, flow.ValidateAll)
+		require.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("Validate any", func(t *testing.T) {
+		invalid := alice.sign(t, 0, message)
+		invalid.KeyIndex = 1
+
+		valid, err := flow.VerifyUserSignatures(message, []flow.CompositeSignature{
+			invalid,
+			alice.sign(t, 0, message),
+		}, alice.keys, flow.ValidateAny)
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		valid, err = flow.VerifyUserSignatures(message, []flow.CompositeSignature{invalid}, alice.keys, flow.ValidateAny)
+		require.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("Invalid inputs", func(t *testing.T) {
+		valid, err := flow.VerifyUserSignatures(message, nil, alice.keys, flow.ValidateAny)
+		require.NoError(t, err)
+		assert.False(t, valid)
+
+		bob := newTestAccount(t, flow.HexToAddress("02"), 1000)
+		_, err = flow.VerifyUserSignatures(message, []flow.CompositeSignature{
+			alice.sign(t, 0, message),
+			bob.sign(t, 0, message),
+		}, alice.keys, flow.ValidateAll)
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifyUserSignaturesAtBlockHeight(t *testing.T) {
+	ctx := context.Background()
+	message := []byte("ananas")
+	alice := newTestAccount(t, flow.HexToAddress("01"), 500, 500)
+	bob := newTestAccount(t, flow.HexToAddress("02"), 1000)
+
+	client := keysAtHeightClient{
+		alice.address: alice.keys,
+		bob.address:   bob.keys,
+	}
+
+	// each account must reach the threshold
+	valid, err := flow.VerifyUserSignaturesAtBlockHeight(ctx, client, 10, message, []flow.CompositeSignature{
+		alice.sign(t, 0, message),
+		alice.sign(t, 1, message),
+		bob.sign(t, 0, message),
+	}, flow.ValidateAll)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = flow.VerifyUserSignaturesAtBlockHeight(ctx, client, 10, message, []flow.CompositeSignature{
+		alice.sign(t, 0, message),
+		bob.sign(t, 0, message),
+	}, flow.ValidateAll)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	valid, err = flow.VerifyUserSignaturesAtBlockHeight(ctx, client, 10, message, []flow.CompositeSignature{
+		alice.sign(t, 0, message),
+	}, flow.ValidateAny)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = flow.VerifyUserSignaturesAtBlockHeight(ctx, client, 10, message, []flow.CompositeSignature{
+		{Address: flow.HexToAddress("03")},
+	}, flow.ValidateAny)
+	assert.Error(t, err)
+}
@@ -0,0 +1,269 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// encodableSnapshot is the JSON representation of a snapshot used by the protocol.
+type encodableSnapshot struct {
+	SealingSegment struct {
+		Blocks               []encodableProposal
+		ExtraBlocks          []encodableProposal
+		ExecutionResults     []encodableExecutionResult
+		LatestSeals          map[flow.Identifier]flow.Identifier
+		FirstSeal            *encodableSeal
+		ProtocolStateEntries map[flow.Identifier]struct {
+			EpochEntry encodableEpochEntry
+		}
+	}
+	QuorumCertificate encodableQuorumCertificate
+	Params            Params
+}
+
+type encodableEpochEntry struct {
+	EpochFallbackTriggered    bool
+	PreviousEpochSetup        *flow.EpochSetup
+	PreviousEpochCommit       *flow.EpochCommit
+	CurrentEpochSetup         *flow.EpochSetup
+	CurrentEpochCommit        *flow.EpochCommit
+	NextEpochSetup            *flow.EpochSetup
+	NextEpochCommit           *flow.EpochCommit
+	CurrentEpochIdentityTable []*Identity
+}
+
+type encodableProposal struct {
+	Block           encodableBlock
+	ProposerSigData []byte
+}
+
+type encodableBlock struct {
+	ChainID            flow.ChainID
+	ParentID           flow.Identifier
+	Height             uint64
+	Timestamp          uint64
+	View               uint64
+	ParentView         uint64
+	ParentVoterIndices []byte
+	ParentVoterSigData []byte
+	ProposerID         flow.Identifier
+	LastViewTC         *encodableTimeoutCertificate
+	Payload            encodablePayload
+	ID                 flow.Identifier
+}
+
+type encodablePayload struct {
+	Guarantees      []encodableGuarantee
+	Seals           []encodableSeal
+	Receipts        []encodableReceipt
+	Results         []encodableExecutionResult
+	ProtocolStateID flow.Identifier
+}
+
+type encodableGuarantee struct {
+	CollectionID     flow.Identifier
+	ReferenceBlockID flow.Identifier
+	ClusterChainID   flow.ChainID
+	SignerIndices    []byte
+	Signature        []byte
+}
+
+type encodableSeal struct {
+	BlockID                flow.Identifier
+	ResultID               flow.Identifier
+	FinalState             flow.Identifier
+	AggregatedApprovalSigs []struct {
+		VerifierSignatures [][]byte
+		SignerIDs          []flow.Identifier
+	}
+}
+
+type encodableReceipt struct {
+	ExecutorID        flow.Identifier
+	ResultID          flow.Identifier
+	Spocks            [][]byte
+	ExecutorSignature []byte
+}
+
+type encodableExecutionResult struct {
+	PreviousResultID flow.Identifier
+	BlockID          flow.Identifier
+	Chunks           []encodableChunk
+	ServiceEvents    []struct {
+		Type  string
+		Event json.RawMessage
+	}
+	ExecutionDataID flow.Identifier
+}
+
+type encodableChunk struct {
+	CollectionIndex      uint
+	StartState           flow.Identifier
+	EventCollection      flow.Identifier
+	BlockID              flow.Identifier
+	TotalComputationUsed uint64
+	NumberOfTransactions uint16
+	Index                uint64
+	EndState             flow.Identifier
+	ServiceEventCount    uint16
+}
+
+type encodableQuorumCertificate struct {
+	View          uint64
+	BlockID       flow.Identifier
+	SignerIndices []byte
+	SigData       []byte
+}
+
+type encodableTimeoutCertificate struct {
+	View          uint64
+	NewestQCViews []uint64
+	NewestQC      encodableQuorumCertificate
+	SignerIndices []byte
+	SigData       []byte
+}
+
+// toBlock converts the proposal to a block with the given status. Timestamps are encoded in
+// Unix milliseconds. An error is returned if the payload hash of the block can't be computed.
+func (p encodableProposal) toBlock(status flow.BlockStatus) (*flow.Block, error) {
+	b := p.Block
+
+	block := &flow.Block{
+		BlockHeader: flow.BlockHeader{
+			ID:                 b.ID,
+			ParentID:           b.ParentID,
+			Height:             b.Height,
+			Timestamp:          time.UnixMilli(int64(b.Timestamp)).UTC(),
+			Status:             status,
+			View:               b.View,
+			ParentVoterSigData: b.ParentVoterSigData,
+			ProposerID:         b.ProposerID,
+			ProposerSigData:    p.ProposerSigData,
+			ChainID:            flow.BytesToID([]byte(b.ChainID)),
+			ParentVoterIndices: b.ParentVoterIndices,
+			ParentView:         b.ParentView,
+		},
+		BlockPayload: b.Payload.toPayload(),
+	}
+	if tc := b.LastViewTC; tc != nil {
+		block.LastViewTimeoutCertificate = flow.TimeoutCertificate{
+			View:          tc.View,
+			HighQCViews:   tc.NewestQCViews,
+			HighestQC:     tc.NewestQC.toQuorumCertificate(),
+			SignerIndices: tc.SignerIndices,
+			SigData:       tc.SigData,
+		}
+	}
+
+	payloadHash, err := block.BlockPayload.ComputeHash(flow.BlockEncodingCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("block %s: %w", b.ID, err)
+	}
+	block.PayloadHash = payloadHash.Bytes()
+
+	return block, nil
+}
+
+func (p encodablePayload) toPayload() flow.BlockPayload {
+	payload := flow.BlockPayload{
+		ProtocolStateID: p.ProtocolStateID,
+	}
+	for _, g := range p.Guarantees {
+		payload.CollectionGuarantees = append(payload.CollectionGuarantees, &flow.CollectionGuarantee{
+			CollectionID:     g.CollectionID,
+			ReferenceBlockID: g.ReferenceBlockID,
+			Signature:        g.Signature,
+			SignerIndices:    g.SignerIndices,
+			ClusterChainID:   g.ClusterChainID,
+		})
+	}
+	for _, s := range p.Seals {
+		payload.Seals = append(payload.Seals, s.toSeal())
+	}
+	for _, r := range p.Receipts {
+		payload.ExecutionReceiptMetaList = append(payload.ExecutionReceiptMetaList, &flow.ExecutionReceiptMeta{
+			ExecutorID:        r.ExecutorID,
+			ResultID:          r.ResultID,
+			Spocks:            r.Spocks,
+			ExecutorSignature: r.ExecutorSignature,
+		})
+	}
+	for _, r := range p.Results {
+		payload.ExecutionResultsList = append(payload.ExecutionResultsList, r.toExecutionResult())
+	}
+	return payload
+}
+
+func (s encodableSeal) toSeal() *flow.BlockSeal {
+	seal := &flow.BlockSeal{
+		BlockID:    s.BlockID,
+		ResultId:   s.ResultID,
+		FinalState: s.FinalState.Bytes(),
+	}
+	for _, sigs := range s.AggregatedApprovalSigs {
+		seal.AggregatedApprovalSigs = append(seal.AggregatedApprovalSigs, &flow.AggregatedSignature{
+			VerifierSignatures: sigs.VerifierSignatures,
+			SignerIds:          sigs.SignerIDs,
+		})
+	}
+	return seal
+}
+
+func (r encodableExecutionResult) toExecutionResult() *flow.ExecutionResult {
+	result := &flow.ExecutionResult{
+		PreviousResultID: r.PreviousResultID,
+		BlockID:          r.BlockID,
+		ExecutionDataID:  r.ExecutionDataID,
+	}
+	for _, c := range r.Chunks {
+		result.Chunks = append(result.Chunks, &flow.Chunk{
+			CollectionIndex:      c.CollectionIndex,
+			StartState:           flow.StateCommitment(c.StartState),
+			EventCollection:      flow.BytesToHash(c.EventCollection.Bytes()),
+			BlockID:              c.BlockID,
+			TotalComputationUsed: c.TotalComputationUsed,
+			NumberOfTransactions: c.NumberOfTransactions,
+			Index:                c.Index,
+			EndState:             flow.StateCommitment(c.EndState),
+			ServiceEventCount:    c.ServiceEventCount,
+		})
+	}
+	// service events are encoded like in the Access API, with a JSON payload
+	for _, e := range r.ServiceEvents {
+		result.ServiceEvents = append(result.ServiceEvents, &flow.ServiceEvent{
+			Type:    e.Type,
+			Payload: e.Event,
+		})
+	}
+	return result
+}
+
+func (qc encodableQuorumCertificate) toQuorumCertificate() flow.QuorumCertificate {
+	return flow.QuorumCertificate{
+		View:          qc.View,
+		BlockID:       qc.BlockID,
+		SignerIndices: qc.SignerIndices,
+		SigData:       qc.SigData,
+	}
+}
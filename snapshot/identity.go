@@ -0,0 +1,71 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"encoding/json"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// ParticipationStatus is the participation of a node in the current epoch.
+type ParticipationStatus string
+
+const (
+	// ParticipationStatusActive is the status of the nodes participating in the current epoch.
+	ParticipationStatusActive ParticipationStatus = "EpochParticipationStatusActive"
+	// ParticipationStatusJoining is the status of the nodes joining the network in the next epoch.
+	ParticipationStatusJoining ParticipationStatus = "EpochParticipationStatusJoining"
+	// ParticipationStatusLeaving is the status of the nodes which left the network at the end
+	// of the previous epoch.
+	ParticipationStatusLeaving ParticipationStatus = "EpochParticipationStatusLeaving"
+	// ParticipationStatusEjected is the status of the nodes ejected from the network.
+	ParticipationStatusEjected ParticipationStatus = "EpochParticipationStatusEjected"
+)
+
+// Identity is a node of the identity table of a snapshot.
+type Identity struct {
+	flow.Identity
+	ParticipationStatus ParticipationStatus
+}
+
+// encodableIdentity is the JSON representation of an identity table entry used by the protocol.
+type encodableIdentity struct {
+	EncodableIdentitySkeleton flow.Identity
+	ParticipationStatus       ParticipationStatus
+}
+
+func (i Identity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodableIdentity{
+		EncodableIdentitySkeleton: i.Identity,
+		ParticipationStatus:       i.ParticipationStatus,
+	})
+}
+
+func (i *Identity) UnmarshalJSON(data []byte) error {
+	var enc encodableIdentity
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+	*i = Identity{
+		Identity:            enc.EncodableIdentitySkeleton,
+		ParticipationStatus: enc.ParticipationStatus,
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package snapshot decodes the protocol state snapshots returned by the Access API.
+//
+// A protocol state snapshot is the state of the protocol at a finalized block: the block
+// itself, the quorum certificate certifying it, the sealing segment of blocks down to the
+// latest sealed block, and the epochs in which it is included with their identity tables.
+// Snapshots are serialized in the JSON encoding of the protocol, and are decoded with Decode.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// Snapshot is a decoded protocol state snapshot.
+type Snapshot struct {
+	// Head is the header of the finalized block of the snapshot.
+	Head flow.BlockHeader
+	// QuorumCertificate is the quorum certificate certifying the head.
+	QuorumCertificate flow.QuorumCertificate
+	// SealingSegment is the chain of blocks from the latest sealed block to the head.
+	SealingSegment SealingSegment
+	// Params are the parameters of the spork of the snapshot.
+	Params Params
+
+	// PreviousEpoch is the epoch preceding the current epoch, or nil for the first epoch of the spork.
+	PreviousEpoch *Epoch
+	// CurrentEpoch is the epoch of the head.
+	CurrentEpoch *Epoch
+	// NextEpoch is the epoch following the current epoch, or nil if it wasn't set up yet
+	// at the head.
+	NextEpoch *Epoch
+	// EpochFallbackTriggered is true if the network entered epoch fallback mode, after a
+	// failure to set up the next epoch.
+	EpochFallbackTriggered bool
+
+	// Identities is the identity table of the current epoch, including the nodes joining
+	// the next epoch and the nodes leaving the previous epoch.
+	Identities []*Identity
+}
+
+// SealingSegment is the chain of blocks of a snapshot, from the latest sealed block to the head.
+type SealingSegment struct {
+	// Blocks are the blocks from the latest sealed block to the head, by ascending height.
+	Blocks []*flow.Block
+	// ExtraBlocks are ancestors of the blocks, by ascending height, holding history such as
+	// the collection guarantees which didn't expire yet.
+	ExtraBlocks []*flow.Block
+	// ExecutionResults are the results referenced by the receipts and seals of the blocks,
+	// but not included in their payloads.
+	ExecutionResults []*flow.ExecutionResult
+	// LatestSeals maps the ID of each block to the ID of the latest seal as of the block.
+	LatestSeals map[flow.Identifier]flow.Identifier
+	// FirstSeal is the latest seal as of the first block, when it isn't included in the blocks.
+	FirstSeal *flow.BlockSeal
+}
+
+// Params are the parameters of the spork of a snapshot.
+type Params struct {
+	ChainID              flow.ChainID
+	SporkID              flow.Identifier
+	SporkRootBlockHeight uint64
+	SporkRootBlockView   uint64
+}
+
+// Epoch is the setup of an epoch, and its commit once the setup is completed.
+type Epoch struct {
+	Setup *flow.EpochSetup
+	// Commit is nil while the epoch setup is in progress.
+	Commit *flow.EpochCommit
+}
+
+// Counter returns the counter of the epoch.
+func (e *Epoch) Counter() uint64 {
+	return e.Setup.Counter
+}
+
+// EpochPhase is the phase of the current epoch, preparing the next epoch.
+type EpochPhase int
+
+const (
+	// EpochPhaseStaking is the phase in which the nodes of the next epoch stake.
+	EpochPhaseStaking EpochPhase = iota
+	// EpochPhaseSetup is the phase in which the next epoch is set up, once its participants are known.
+	EpochPhaseSetup
+	// EpochPhaseCommitted is the phase in which the next epoch is ready to start.
+	EpochPhaseCommitted
+)
+
+func (p EpochPhase) String() string {
+	switch p {
+	case EpochPhaseStaking:
+		return "staking"
+	case EpochPhaseSetup:
+		return "setup"
+	case EpochPhaseCommitted:
+		return "committed"
+	default:
+		return "unknown"
+	}
+}
+
+// Epoch returns the epoch of the head of the snapshot.
+func (s *Snapshot) Epoch() *Epoch {
+	return s.CurrentEpoch
+}
+
+// EpochPhase returns the phase of the epoch of the head of the snapshot.
+func (s *Snapshot) EpochPhase() EpochPhase {
+	switch {
+	case s.NextEpoch == nil:
+		return EpochPhaseStaking
+	case s.NextEpoch.Commit == nil:
+		return EpochPhaseSetup
+	default:
+		return EpochPhaseCommitted
+	}
+}
+
+// SealedHead returns the header of the latest sealed block as of the head of the snapshot,
+// which is the first block of the sealing segment.
+func (s *Snapshot) SealedHead() flow.BlockHeader {
+	return s.SealingSegment.Blocks[0].BlockHeader
+}
+
+// NodesByRole returns the identities of the nodes with the given role, in the order of the
+// identity table.
+func (s *Snapshot) NodesByRole(role flow.Role) []*Identity {
+	var nodes []*Identity
+	for _, identity := range s.Identities {
+		if identity.Role == role {
+			nodes = append(nodes, identity)
+		}
+	}
+	return nodes
+}
+
+// Decode decodes a protocol state snapshot in the JSON encoding returned by the Access API.
+//
+// The IDs of the blocks are decoded from the snapshot. The payload hashes of the block headers
+// aren't part of the encoding and are computed from the payloads.
+func Decode(data []byte) (*Snapshot, error) {
+	var enc encodableSnapshot
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("snapshot: failed to decode snapshot: %w", err)
+	}
+
+	segment := enc.SealingSegment
+	if len(segment.Blocks) == 0 {
+		return nil, fmt.Errorf("snapshot: sealing segment has no blocks")
+	}
+	head := segment.Blocks[len(segment.Blocks)-1].Block
+
+	entry, ok := segment.ProtocolStateEntries[head.Payload.ProtocolStateID]
+	if !ok {
+		return nil, fmt.Errorf("snapshot: missing protocol state %s of head", head.Payload.ProtocolStateID)
+	}
+	epochs := entry.EpochEntry
+	if epochs.CurrentEpochSetup == nil || epochs.CurrentEpochCommit == nil {
+		return nil, fmt.Errorf("snapshot: missing current epoch of head")
+	}
+
+	snapshot := &Snapshot{
+		QuorumCertificate: enc.QuorumCertificate.toQuorumCertificate(),
+		SealingSegment: SealingSegment{
+			LatestSeals: segment.LatestSeals,
+		},
+		Params: enc.Params,
+		CurrentEpoch: &Epoch{
+			Setup:  epochs.CurrentEpochSetup,
+			Commit: epochs.CurrentEpochCommit,
+		},
+		EpochFallbackTriggered: epochs.EpochFallbackTriggered,
+		Identities:             epochs.CurrentEpochIdentityTable,
+	}
+	if epochs.PreviousEpochSetup != nil {
+		snapshot.PreviousEpoch = &Epoch{
+			Setup:  epochs.PreviousEpochSetup,
+			Commit: epochs.PreviousEpochCommit,
+		}
+	}
+	if epochs.NextEpochSetup != nil {
+		snapshot.NextEpoch = &Epoch{
+			Setup:  epochs.NextEpochSetup,
+			Commit: epochs.NextEpochCommit,
+		}
+	}
+
+	// the first block is sealed, like the extra blocks preceding it
+	for i, proposal := range segment.Blocks {
+		status := flow.BlockStatusFinalized
+		if i == 0 {
+			status = flow.BlockStatusSealed
+		}
+		block, err := proposal.toBlock(status)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: invalid sealing segment: %w", err)
+		}
+		snapshot.SealingSegment.Blocks = append(snapshot.SealingSegment.Blocks, block)
+	}
+	for _, proposal := range segment.ExtraBlocks {
+		block, err := proposal.toBlock(flow.BlockStatusSealed)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: invalid sealing segment: %w", err)
+		}
+		snapshot.SealingSegment.ExtraBlocks = append(snapshot.SealingSegment.ExtraBlocks, block)
+	}
+	for _, result := range segment.ExecutionResults {
+		snapshot.SealingSegment.ExecutionResults = append(snapshot.SealingSegment.ExecutionResults, result.toExecutionResult())
+	}
+	if segment.FirstSeal != nil {
+		snapshot.SealingSegment.FirstSeal = segment.FirstSeal.toSeal()
+	}
+	snapshot.Head = snapshot.SealingSegment.Blocks[len(snapshot.SealingSegment.Blocks)-1].BlockHeader
+
+	return snapshot, nil
+}
@@ -0,0 +1,196 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/snapshot"
+)
+
+// decodeTestSnapshot decodes a snapshot generated with flow-go: a root snapshot extended with
+// two blocks, the second sealing the root block, during the committed phase of the epoch.
+func decodeTestSnapshot(t *testing.T) *snapshot.Snapshot {
+	data, err := os.ReadFile("testdata/snapshot.json")
+	require.NoError(t, err)
+
+	snap, err := snapshot.Decode(data)
+	require.NoError(t, err)
+	return snap
+}
+
+func TestDecode(t *testing.T) {
+	snap := decodeTestSnapshot(t)
+
+	rootID := flow.HexToID("b2d7acd058a37c6cde5489d33fc0bc1240f3b61c3487a3824946cffb84e4fa5f")
+	headID := flow.HexToID("e001a2a0286520e2f6a28ce9ce0fede21aad77fb1b37be7bd5b1c37891810f9b")
+
+	t.Run("Head", func(t *testing.T) {
+		assert.Equal(t, headID, snap.Head.ID)
+		assert.Equal(t, uint64(2), snap.Head.Height)
+		assert.Equal(t, flow.BytesToID([]byte(flow.Emulator)), snap.Head.ChainID)
+		assert.Equal(t,
+			flow.HexToID("4af7d7aa473e8f602b30e2f5e1b07dea44d778d7325a959544a21152c78c268c").Bytes(),
+			snap.Head.PayloadHash,
+		)
+
+		version, err := snap.Head.CheckID()
+		require.NoError(t, err)
+		assert.Equal(t, flow.BlockEncodingCurrent, version)
+
+		assert.Equal(t, headID, snap.QuorumCertificate.BlockID)
+		assert.Equal(t, snap.Head.View, snap.QuorumCertificate.View)
+	})
+
+	t.Run("Sealing segment", func(t *testing.T) {
+		segment := snap.SealingSegment
+		require.Len(t, segment.Blocks, 3)
+		assert.Empty(t, segment.ExtraBlocks)
+
+		sealed := snap.SealedHead()
+		assert.Equal(t, rootID, sealed.ID)
+		assert.Equal(t, flow.BlockStatusSealed, sealed.Status)
+		assert.Equal(t, flow.BlockStatusFinalized, segment.Blocks[1].Status)
+
+		first := segment.Blocks[1]
+		assert.Equal(t, rootID, first.ParentID)
+		assert.NotEmpty(t, first.ProposerSigData)
+		assert.Len(t, first.CollectionGuarantees, 2)
+		assert.Equal(t, flow.ChainID("cluster-1-00000000"), first.CollectionGuarantees[0].ClusterChainID)
+		require.Len(t, first.ExecutionReceiptMetaList, 1)
+		require.Len(t, first.ExecutionResultsList, 1)
+
+		result := first.ExecutionResultsList[0]
+		assert.Equal(t, rootID, result.BlockID)
+		assert.Equal(t, result.BlockID, result.Chunks[0].BlockID)
+		assert.Equal(t, first.ExecutionReceiptMetaList[0].ResultID, segment.Blocks[2].Seals[0].ResultId)
+		resultID, err := result.ID()
+		require.NoError(t, err)
+		assert.Equal(t, first.ExecutionReceiptMetaList[0].ResultID, resultID)
+
+		seal := segment.Blocks[2].Seals[0]
+		assert.Equal(t, rootID, seal.BlockID)
+		assert.Equal(t, result.Chunks[len(result.Chunks)-1].EndState, flow.BytesToStateCommitment(seal.FinalState))
+		assert.NotEmpty(t, seal.AggregatedApprovalSigs[0].SignerIds)
+
+		assert.Equal(t,
+			flow.HexToID("5670506545852f845503e0462f7cb48c1a1a44d79d7c72b68aa63be4fad6885c"),
+			segment.LatestSeals[headID],
+		)
+		require.NotNil(t, segment.FirstSeal)
+		assert.Equal(t, rootID, segment.FirstSeal.BlockID)
+
+		// the root result holds the service events of the first epoch
+		require.Len(t, segment.ExecutionResults, 1)
+		events := segment.ExecutionResults[0].ServiceEvents
+		require.Len(t, events, 2)
+		assert.Equal(t, "setup", events[0].Type)
+		var setup flow.EpochSetup
+		require.NoError(t, json.Unmarshal(events[0].Payload, &setup))
+		assert.Equal(t, snap.CurrentEpoch.Counter(), setup.Counter)
+		rootResultID, err := segment.ExecutionResults[0].ID()
+		require.NoError(t, err)
+		assert.Equal(t, segment.FirstSeal.ResultId, rootResultID)
+
+		for _, block := range segment.Blocks {
+			assert.NoError(t, block.CheckIntegrity(), block.ID)
+		}
+	})
+
+	t.Run("Epochs", func(t *testing.T) {
+		assert.Nil(t, snap.PreviousEpoch)
+		assert.Same(t, snap.CurrentEpoch, snap.Epoch())
+		assert.Equal(t, uint64(100000), snap.Epoch().Setup.FinalView)
+		require.NotNil(t, snap.NextEpoch)
+		assert.Equal(t, snap.Epoch().Counter()+1, snap.NextEpoch.Counter())
+		assert.Equal(t, uint64(100001), snap.NextEpoch.Setup.FirstView)
+		assert.Equal(t, snapshot.EpochPhaseCommitted, snap.EpochPhase())
+		assert.False(t, snap.EpochFallbackTriggered)
+
+		commit := snap.Epoch().Commit
+		assert.Equal(t, snap.Epoch().Counter(), commit.Counter)
+		assert.Len(t, commit.DKGParticipantKeys, len(commit.DKGIndexMap))
+		assert.NotNil(t, commit.DKGGroupKey)
+	})
+
+	t.Run("Identities", func(t *testing.T) {
+		require.Len(t, snap.Identities, 11)
+
+		consensus := snap.NodesByRole(flow.RoleConsensus)
+		require.Len(t, consensus, 2)
+		for _, node := range consensus {
+			assert.Equal(t, snapshot.ParticipationStatusActive, node.ParticipationStatus)
+			assert.Equal(t, crypto.BLS_BLS12_381, node.StakingKey.Algorithm())
+			assert.NotZero(t, node.InitialWeight)
+		}
+
+		statuses := make(map[flow.Identifier]snapshot.ParticipationStatus)
+		for _, node := range snap.Identities {
+			statuses[node.NodeID] = node.ParticipationStatus
+		}
+		assert.Equal(t, snapshot.ParticipationStatusLeaving,
+			statuses[flow.HexToID("5af01a9f962b71b82575a586dd436de947b580d870d32a87cdebeefb899099bf")])
+		assert.Equal(t, snapshot.ParticipationStatusJoining,
+			statuses[flow.HexToID("f9212a929d4d627cf0d81295c9d52b2b8ff60eb9994cefe5d701450031090af5")])
+		assert.Len(t, snap.NodesByRole(flow.RoleExecution), 3)
+	})
+}
+
+func TestIdentity_JSON(t *testing.T) {
+	snap := decodeTestSnapshot(t)
+	identity := snap.Identities[0]
+
+	data, err := json.Marshal(identity)
+	require.NoError(t, err)
+
+	var decoded snapshot.Identity
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, identity.NodeID, decoded.NodeID)
+	assert.Equal(t, identity.ParticipationStatus, decoded.ParticipationStatus)
+	assert.True(t, identity.StakingKey.Equals(decoded.StakingKey))
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	_, err := snapshot.Decode([]byte("{}"))
+	assert.Error(t, err)
+
+	_, err = snapshot.Decode([]byte("not json"))
+	assert.Error(t, err)
+
+	// the payload hash of a block including a result with an unknown service event can't be computed
+	data, err := os.ReadFile("testdata/snapshot.json")
+	require.NoError(t, err)
+	var enc map[string]any
+	require.NoError(t, json.Unmarshal(data, &enc))
+	blocks := enc["SealingSegment"].(map[string]any)["Blocks"].([]any)
+	result := blocks[1].(map[string]any)["Block"].(map[string]any)["Payload"].(map[string]any)["Results"].([]any)[0]
+	result.(map[string]any)["ServiceEvents"] = []any{map[string]any{"Type": "unknown", "Event": map[string]any{}}}
+	data, err = json.Marshal(enc)
+	require.NoError(t, err)
+
+	_, err = snapshot.Decode(data)
+	assert.ErrorIs(t, err, flow.ErrUnknownServiceEvent)
+}
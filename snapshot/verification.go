@@ -0,0 +1,206 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/onflow/crypto"
+	"github.com/onflow/crypto/hash"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+const (
+	// executionReceiptTag is the domain separation tag of executor signatures on receipts.
+	executionReceiptTag = "FLOW-Execution_Receipt-V00-CS00-with-"
+	// resultApprovalTag is the domain separation tag of verifier signatures on result approvals.
+	resultApprovalTag = "FLOW-Result_Approval-V00-CS00-with-"
+)
+
+var (
+	// ErrInvalidExecutionReceipt is returned when an execution receipt isn't signed by its executor.
+	ErrInvalidExecutionReceipt = errors.New("snapshot: invalid execution receipt")
+	// ErrInvalidSeal is returned when a seal doesn't match its execution result, or isn't approved
+	// by verification nodes.
+	ErrInvalidSeal = errors.New("snapshot: invalid seal")
+)
+
+// unsignedReceipt is the part of an execution receipt signed by its executor.
+type unsignedReceipt struct {
+	ExecutorID flow.Identifier
+	ResultID   flow.Identifier
+	Spocks     [][]byte
+}
+
+// attestation is the part of a result approval signed by a verifier, approving a chunk of a result.
+type attestation struct {
+	BlockID           flow.Identifier
+	ExecutionResultID flow.Identifier
+	ChunkIndex        uint64
+}
+
+// VerifyExecutionReceipt verifies the signature of the executor of the receipt, which must be an
+// execution node of the identity table of the snapshot.
+//
+// The identity table is the table of the epoch of the head, so receipts of blocks of previous
+// epochs are only verified if their executors are still staked.
+func (s *Snapshot) VerifyExecutionReceipt(receipt *flow.ExecutionReceiptMeta) error {
+	executor, err := s.stakedNode(receipt.ExecutorID, flow.RoleExecution)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidExecutionReceipt, err)
+	}
+
+	id, err := makeID(unsignedReceipt{
+		ExecutorID: receipt.ExecutorID,
+		ResultID:   receipt.ResultID,
+		Spocks:     receipt.Spocks,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidExecutionReceipt, err)
+	}
+
+	valid, err := executor.StakingKey.Verify(receipt.ExecutorSignature, id[:], crypto.NewExpandMsgXOFKMAC128(executionReceiptTag))
+	if err != nil {
+		return fmt.Errorf("%w: failed to verify signature of executor %s: %w", ErrInvalidExecutionReceipt, executor.NodeID, err)
+	}
+	if !valid {
+		return fmt.Errorf("%w: invalid signature of executor %s", ErrInvalidExecutionReceipt, executor.NodeID)
+	}
+	return nil
+}
+
+// VerifySeal verifies that the seal seals the execution result: the seal must reference the ID
+// of the result, its final state must be the end state of the last chunk of the result, and each
+// chunk must be approved by verification nodes of the identity table of the snapshot.
+//
+// The number of approvals required for each chunk depends on the assignment of the chunks to the
+// verification nodes, which isn't part of the snapshot, so only one approval per chunk is required.
+func (s *Snapshot) VerifySeal(seal *flow.BlockSeal, result *flow.ExecutionResult) error {
+	resultID, err := result.ID()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSeal, err)
+	}
+	if seal.ResultId != resultID {
+		return fmt.Errorf("%w: seal of result %s doesn't match result %s", ErrInvalidSeal, seal.ResultId, resultID)
+	}
+	if seal.BlockID != result.BlockID {
+		return fmt.Errorf("%w: seal of block %s doesn't match result of block %s", ErrInvalidSeal, seal.BlockID, result.BlockID)
+	}
+	if len(result.Chunks) == 0 {
+		return fmt.Errorf("%w: result has no chunks", ErrInvalidSeal)
+	}
+	final := result.Chunks[len(result.Chunks)-1].EndState
+	if !bytes.Equal(seal.FinalState, final[:]) {
+		return fmt.Errorf("%w: final state %x doesn't match end state %x of the last chunk", ErrInvalidSeal, seal.FinalState, final[:])
+	}
+	if len(seal.AggregatedApprovalSigs) != len(result.Chunks) {
+		return fmt.Errorf(
+			"%w: seal has %d aggregated approvals for %d chunks",
+			ErrInvalidSeal, len(seal.AggregatedApprovalSigs), len(result.Chunks),
+		)
+	}
+
+	hasher := crypto.NewExpandMsgXOFKMAC128(resultApprovalTag)
+	for i, chunk := range result.Chunks {
+		if chunk.Index != uint64(i) {
+			return fmt.Errorf("%w: chunk %d has index %d", ErrInvalidSeal, i, chunk.Index)
+		}
+		if err := s.verifyApprovals(seal.AggregatedApprovalSigs[i], chunk, resultID, hasher); err != nil {
+			return fmt.Errorf("%w: chunk %d: %w", ErrInvalidSeal, i, err)
+		}
+	}
+	return nil
+}
+
+// verifyApprovals verifies the signatures of the verifiers approving the chunk of the result.
+func (s *Snapshot) verifyApprovals(sigs *flow.AggregatedSignature, chunk *flow.Chunk, resultID flow.Identifier, hasher hash.Hasher) error {
+	if sigs == nil || len(sigs.SignerIds) == 0 {
+		return fmt.Errorf("no approvals")
+	}
+	if len(sigs.SignerIds) != len(sigs.VerifierSignatures) {
+		return fmt.Errorf("%d signatures for %d signers", len(sigs.VerifierSignatures), len(sigs.SignerIds))
+	}
+
+	id, err := makeID(attestation{
+		BlockID:           chunk.BlockID,
+		ExecutionResultID: resultID,
+		ChunkIndex:        chunk.Index,
+	})
+	if err != nil {
+		return err
+	}
+
+	signed := make(map[flow.Identifier]bool, len(sigs.SignerIds))
+	for i, signerID := range sigs.SignerIds {
+		if signed[signerID] {
+			return fmt.Errorf("duplicate approval of verifier %s", signerID)
+		}
+		signed[signerID] = true
+
+		verifier, err := s.stakedNode(signerID, flow.RoleVerification)
+		if err != nil {
+			return err
+		}
+		valid, err := verifier.StakingKey.Verify(sigs.VerifierSignatures[i], id[:], hasher)
+		if err != nil {
+			return fmt.Errorf("failed to verify signature of verifier %s: %w", signerID, err)
+		}
+		if !valid {
+			return fmt.Errorf("invalid signature of verifier %s", signerID)
+		}
+	}
+	return nil
+}
+
+// stakedNode returns the identity of the node with the given role, if it has a positive weight and
+// participates in the epoch of the head or left at the end of the previous epoch.
+func (s *Snapshot) stakedNode(nodeID flow.Identifier, role flow.Role) (*Identity, error) {
+	for _, identity := range s.Identities {
+		if identity.NodeID != nodeID {
+			continue
+		}
+		if identity.Role != role {
+			return nil, fmt.Errorf("node %s is a %s node, not a %s node", nodeID, identity.Role, role)
+		}
+		status := identity.ParticipationStatus
+		if status != ParticipationStatusActive && status != ParticipationStatusLeaving {
+			return nil, fmt.Errorf("node %s has participation status %s", nodeID, status)
+		}
+		if identity.InitialWeight == 0 {
+			return nil, fmt.Errorf("node %s has no weight", nodeID)
+		}
+		if identity.StakingKey == nil {
+			return nil, fmt.Errorf("node %s has no staking key", nodeID)
+		}
+		return identity, nil
+	}
+	return nil, fmt.Errorf("node %s isn't in the identity table", nodeID)
+}
+
+// makeID returns the ID of an entity of the protocol, the SHA3-256 hash of its RLP encoding.
+func makeID(entity interface{}) (flow.Identifier, error) {
+	b, err := rlp.EncodeToBytes(entity)
+	if err != nil {
+		return flow.EmptyID, fmt.Errorf("failed to encode entity: %w", err)
+	}
+	return flow.HashToID(hash.NewSHA3_256().ComputeHash(b)), nil
+}
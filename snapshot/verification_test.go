@@ -0,0 +1,235 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package snapshot_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/snapshot"
+)
+
+// sealVector is an execution result generated with flow-go, with a receipt signed by an execution
+// node and a seal aggregating the approvals of verification nodes.
+type sealVector struct {
+	Identities []struct {
+		NodeID     flow.Identifier
+		Role       flow.Role
+		StakingKey string
+	}
+	Result struct {
+		ID               flow.Identifier
+		PreviousResultID flow.Identifier
+		BlockID          flow.Identifier
+		ExecutionDataID  flow.Identifier
+		Chunks           []struct {
+			CollectionIndex      uint
+			StartState           string
+			EventCollection      string
+			BlockID              flow.Identifier
+			TotalComputationUsed uint64
+			NumberOfTransactions uint16
+			Index                uint64
+			EndState             string
+			ServiceEventCount    uint16
+		}
+	}
+	Receipt struct {
+		ExecutorID        flow.Identifier
+		ResultID          flow.Identifier
+		Spocks            []string
+		ExecutorSignature string
+	}
+	Seal struct {
+		BlockID                flow.Identifier
+		ResultID               flow.Identifier
+		FinalState             string
+		AggregatedApprovalSigs []struct {
+			VerifierSignatures []string
+			SignerIDs          []flow.Identifier
+		}
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func loadSealVector(t *testing.T) (*snapshot.Snapshot, *flow.ExecutionReceiptMeta, *flow.BlockSeal, *flow.ExecutionResult) {
+	data, err := os.ReadFile("testdata/seal.json")
+	require.NoError(t, err)
+
+	var vector sealVector
+	require.NoError(t, json.Unmarshal(data, &vector))
+
+	snap := &snapshot.Snapshot{}
+	for _, identity := range vector.Identities {
+		key, err := crypto.DecodePublicKeyHex(crypto.BLS_BLS12_381, identity.StakingKey)
+		require.NoError(t, err)
+		snap.Identities = append(snap.Identities, &snapshot.Identity{
+			Identity: flow.Identity{
+				NodeID:        identity.NodeID,
+				Role:          identity.Role,
+				InitialWeight: 100,
+				StakingKey:    key,
+			},
+			ParticipationStatus: snapshot.ParticipationStatusActive,
+		})
+	}
+
+	receipt := &flow.ExecutionReceiptMeta{
+		ExecutorID:        vector.Receipt.ExecutorID,
+		ResultID:          vector.Receipt.ResultID,
+		ExecutorSignature: mustDecodeHex(t, vector.Receipt.ExecutorSignature),
+	}
+	for _, spock := range vector.Receipt.Spocks {
+		receipt.Spocks = append(receipt.Spocks, mustDecodeHex(t, spock))
+	}
+
+	result := &flow.ExecutionResult{
+		PreviousResultID: vector.Result.PreviousResultID,
+		BlockID:          vector.Result.BlockID,
+		ExecutionDataID:  vector.Result.ExecutionDataID,
+	}
+	for _, chunk := range vector.Result.Chunks {
+		result.Chunks = append(result.Chunks, &flow.Chunk{
+			CollectionIndex:      chunk.CollectionIndex,
+			StartState:           flow.HexToStateCommitment(chunk.StartState),
+			EventCollection:      mustDecodeHex(t, chunk.EventCollection),
+			BlockID:              chunk.BlockID,
+			TotalComputationUsed: chunk.TotalComputationUsed,
+			NumberOfTransactions: chunk.NumberOfTransactions,
+			Index:                chunk.Index,
+			EndState:             flow.HexToStateCommitment(chunk.EndState),
+			ServiceEventCount:    chunk.ServiceEventCount,
+		})
+	}
+
+	seal := &flow.BlockSeal{
+		BlockID:    vector.Seal.BlockID,
+		ResultId:   vector.Seal.ResultID,
+		FinalState: mustDecodeHex(t, vector.Seal.FinalState),
+	}
+	for _, aggregated := range vector.Seal.AggregatedApprovalSigs {
+		sigs := &flow.AggregatedSignature{SignerIds: aggregated.SignerIDs}
+		for _, sig := range aggregated.VerifierSignatures {
+			sigs.VerifierSignatures = append(sigs.VerifierSignatures, mustDecodeHex(t, sig))
+		}
+		seal.AggregatedApprovalSigs = append(seal.AggregatedApprovalSigs, sigs)
+	}
+
+	return snap, receipt, seal, result
+}
+
+func TestSnapshot_VerifyExecutionReceipt(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		snap, receipt, _, _ := loadSealVector(t)
+		assert.NoError(t, snap.VerifyExecutionReceipt(receipt))
+	})
+
+	t.Run("Forged result", func(t *testing.T) {
+		snap, receipt, _, _ := loadSealVector(t)
+		receipt.ResultID[0] ^= 1
+		assert.ErrorIs(t, snap.VerifyExecutionReceipt(receipt), snapshot.ErrInvalidExecutionReceipt)
+	})
+
+	t.Run("Other executor", func(t *testing.T) {
+		snap, receipt, _, _ := loadSealVector(t)
+		receipt.ExecutorID = snap.NodesByRole(flow.RoleExecution)[1].NodeID
+		assert.ErrorIs(t, snap.VerifyExecutionReceipt(receipt), snapshot.ErrInvalidExecutionReceipt)
+	})
+
+	t.Run("Unstaked executor", func(t *testing.T) {
+		snap, receipt, _, _ := loadSealVector(t)
+		snap.NodesByRole(flow.RoleExecution)[0].ParticipationStatus = snapshot.ParticipationStatusEjected
+		assert.ErrorIs(t, snap.VerifyExecutionReceipt(receipt), snapshot.ErrInvalidExecutionReceipt)
+
+		snap.Identities = snap.Identities[1:]
+		assert.ErrorIs(t, snap.VerifyExecutionReceipt(receipt), snapshot.ErrInvalidExecutionReceipt)
+	})
+}
+
+func TestSnapshot_VerifySeal(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		assert.NoError(t, snap.VerifySeal(seal, result))
+	})
+
+	t.Run("Final state", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		seal.FinalState = result.Chunks[0].EndState[:]
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+	})
+
+	t.Run("Other block", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		seal.BlockID[0] ^= 1
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+	})
+
+	t.Run("Forged result ID", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		seal.ResultId[0] ^= 1
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+	})
+
+	t.Run("Forged result", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		result.Chunks[0].TotalComputationUsed++
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+	})
+
+	t.Run("Missing approvals", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		seal.AggregatedApprovalSigs[1] = &flow.AggregatedSignature{}
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+
+		seal.AggregatedApprovalSigs = seal.AggregatedApprovalSigs[:2]
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+	})
+
+	t.Run("Swapped approvals", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		sigs := seal.AggregatedApprovalSigs
+		sigs[0], sigs[1] = sigs[1], sigs[0]
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+	})
+
+	t.Run("Approval by an execution node", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		seal.AggregatedApprovalSigs[0].SignerIds[0] = snap.NodesByRole(flow.RoleExecution)[0].NodeID
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+	})
+
+	t.Run("Duplicate approval", func(t *testing.T) {
+		snap, _, seal, result := loadSealVector(t)
+		sigs := seal.AggregatedApprovalSigs[0]
+		sigs.SignerIds[1] = sigs.SignerIds[0]
+		sigs.VerifierSignatures[1] = sigs.VerifierSignatures[0]
+		assert.ErrorIs(t, snap.VerifySeal(seal, result), snapshot.ErrInvalidSeal)
+	})
+}
@@ -0,0 +1,340 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package storage
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+)
+
+// exporter converts stored values to Cadence values.
+//
+// The declarations of the composite types aren't available, so the kinds of the composite types
+// are learned from the values, and the types of the fields of composite values are the types of
+// the field values. The composite and interface types of no decoded value are exported as
+// structure types.
+type exporter struct {
+	interpreter *interpreter.Interpreter
+	// kinds are the kinds of the composite types of the exported values, by type ID.
+	kinds map[common.TypeID]common.CompositeKind
+}
+
+func newExporter(inter *interpreter.Interpreter) *exporter {
+	return &exporter{
+		interpreter: inter,
+		kinds:       make(map[common.TypeID]common.CompositeKind),
+	}
+}
+
+func (e *exporter) export(value interpreter.Value) (cadence.Value, error) {
+	switch v := value.(type) {
+	case interpreter.NilValue:
+		return cadence.NewOptional(nil), nil
+	case *interpreter.SomeValue:
+		inner, err := e.export(v.InnerValue())
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(inner), nil
+	case interpreter.VoidValue:
+		return cadence.Void{}, nil
+	case interpreter.BoolValue:
+		return cadence.Bool(v), nil
+	case *interpreter.StringValue:
+		return cadence.String(v.Str), nil
+	case interpreter.CharacterValue:
+		return cadence.Character(v.Str), nil
+	case interpreter.AddressValue:
+		return cadence.Address(v), nil
+	case interpreter.PathValue:
+		return cadence.Path{Domain: v.Domain, Identifier: v.Identifier}, nil
+	case interpreter.TypeValue:
+		if v.Type == nil {
+			return cadence.NewTypeValue(nil), nil
+		}
+		t, err := e.exportType(v.Type)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewTypeValue(t), nil
+	case *interpreter.IDCapabilityValue:
+		borrowType, err := e.exportType(v.BorrowType)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewCapability(cadence.UInt64(v.ID), cadence.Address(v.Address()), borrowType), nil
+	case *interpreter.ArrayValue:
+		return e.exportArray(v)
+	case *interpreter.DictionaryValue:
+		return e.exportDictionary(v)
+	case *interpreter.CompositeValue:
+		return e.exportComposite(v)
+	case interpreter.NumberValue:
+		return exportNumber(e.interpreter, v)
+	}
+	return nil, fmt.Errorf("unsupported value %T", value)
+}
+
+func exportNumber(gauge common.MemoryGauge, value interpreter.NumberValue) (cadence.Value, error) {
+	switch v := value.(type) {
+	case interpreter.IntValue:
+		return cadence.NewIntFromBig(v.ToBigInt(gauge)), nil
+	case interpreter.Int8Value:
+		return cadence.Int8(v), nil
+	case interpreter.Int16Value:
+		return cadence.Int16(v), nil
+	case interpreter.Int32Value:
+		return cadence.Int32(v), nil
+	case interpreter.Int64Value:
+		return cadence.Int64(v), nil
+	case interpreter.Int128Value:
+		return cadence.NewInt128FromBig(v.ToBigInt(gauge))
+	case interpreter.Int256Value:
+		return cadence.NewInt256FromBig(v.ToBigInt(gauge))
+	case interpreter.UIntValue:
+		return cadence.NewUIntFromBig(v.ToBigInt(gauge))
+	case interpreter.UInt8Value:
+		return cadence.UInt8(v), nil
+	case interpreter.UInt16Value:
+		return cadence.UInt16(v), nil
+	case interpreter.UInt32Value:
+		return cadence.UInt32(v), nil
+	case interpreter.UInt64Value:
+		return cadence.UInt64(v), nil
+	case interpreter.UInt128Value:
+		return cadence.NewUInt128FromBig(v.ToBigInt(gauge))
+	case interpreter.UInt256Value:
+		return cadence.NewUInt256FromBig(v.ToBigInt(gauge))
+	case interpreter.Word8Value:
+		return cadence.Word8(v), nil
+	case interpreter.Word16Value:
+		return cadence.Word16(v), nil
+	case interpreter.Word32Value:
+		return cadence.Word32(v), nil
+	case interpreter.Word64Value:
+		return cadence.Word64(v), nil
+	case interpreter.Word128Value:
+		return cadence.NewWord128FromBig(v.ToBigInt(gauge))
+	case interpreter.Word256Value:
+		return cadence.NewWord256FromBig(v.ToBigInt(gauge))
+	case interpreter.Fix64Value:
+		return cadence.Fix64(v), nil
+	case interpreter.Fix128Value:
+		return cadence.Fix128(v), nil
+	case interpreter.UFix64Value:
+		return cadence.UFix64(v.UFix64Value), nil
+	case interpreter.UFix128Value:
+		return cadence.UFix128(v), nil
+	}
+	return nil, fmt.Errorf("unsupported number %T", value)
+}
+
+func (e *exporter) exportArray(array *interpreter.ArrayValue) (cadence.Value, error) {
+	values := make([]cadence.Value, 0, array.Count())
+	var err error
+	array.Iterate(
+		e.interpreter,
+		func(element interpreter.Value) bool {
+			var value cadence.Value
+			value, err = e.export(element)
+			values = append(values, value)
+			return err == nil
+		},
+		false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// the types of the elements are exported first, for the kinds of their composite types
+	t, err := e.exportType(array.Type)
+	if err != nil {
+		return nil, err
+	}
+	return cadence.NewArray(values).WithType(t.(cadence.ArrayType)), nil
+}
+
+func (e *exporter) exportDictionary(dictionary *interpreter.DictionaryValue) (cadence.Value, error) {
+	pairs := make([]cadence.KeyValuePair, 0, dictionary.Count())
+	var err error
+	dictionary.Iterate(
+		e.interpreter,
+		func(key, value interpreter.Value) bool {
+			var pair cadence.KeyValuePair
+			if pair.Key, err = e.export(key); err != nil {
+				return false
+			}
+			if pair.Value, err = e.export(value); err != nil {
+				return false
+			}
+			pairs = append(pairs, pair)
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := e.exportType(dictionary.Type)
+	if err != nil {
+		return nil, err
+	}
+	return cadence.NewDictionary(pairs).WithType(t.(*cadence.DictionaryType)), nil
+}
+
+func (e *exporter) exportComposite(composite *interpreter.CompositeValue) (cadence.Value, error) {
+	e.kinds[composite.TypeID()] = composite.Kind
+
+	var (
+		fields []cadence.Field
+		values []cadence.Value
+		err    error
+	)
+	composite.ForEachField(e.interpreter, func(name string, field interpreter.Value) bool {
+		var value cadence.Value
+		if value, err = e.export(field); err != nil {
+			err = fmt.Errorf("field %s of %s: %w", name, composite.TypeID(), err)
+			return false
+		}
+		fields = append(fields, cadence.Field{Identifier: name, Type: value.Type()})
+		values = append(values, value)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	location, identifier := composite.Location, composite.QualifiedIdentifier
+	switch composite.Kind {
+	case common.CompositeKindStructure:
+		return cadence.NewStruct(values).WithType(cadence.NewStructType(location, identifier, fields, nil)), nil
+	case common.CompositeKindResource:
+		return cadence.NewResource(values).WithType(cadence.NewResourceType(location, identifier, fields, nil)), nil
+	case common.CompositeKindAttachment:
+		return cadence.NewAttachment(values).WithType(cadence.NewAttachmentType(location, identifier, nil, fields, nil)), nil
+	case common.CompositeKindEvent:
+		return cadence.NewEvent(values).WithType(cadence.NewEventType(location, identifier, fields, nil)), nil
+	case common.CompositeKindContract:
+		return cadence.NewContract(values).WithType(cadence.NewContractType(location, identifier, fields, nil)), nil
+	case common.CompositeKindEnum:
+		return cadence.NewEnum(values).WithType(cadence.NewEnumType(location, identifier, nil, fields, nil)), nil
+	}
+	return nil, fmt.Errorf("unsupported composite kind %s of %s", composite.Kind, composite.TypeID())
+}
+
+func (e *exporter) exportType(staticType interpreter.StaticType) (cadence.Type, error) {
+	switch t := staticType.(type) {
+	case nil:
+		return nil, nil
+	case interpreter.PrimitiveStaticType:
+		return cadence.PrimitiveType(t), nil
+	case *interpreter.OptionalStaticType:
+		inner, err := e.exportType(t.Type)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptionalType(inner), nil
+	case *interpreter.VariableSizedStaticType:
+		element, err := e.exportType(t.Type)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewVariableSizedArrayType(element), nil
+	case *interpreter.ConstantSizedStaticType:
+		element, err := e.exportType(t.Type)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewConstantSizedArrayType(uint(t.Size), element), nil
+	case *interpreter.DictionaryStaticType:
+		key, err := e.exportType(t.KeyType)
+		if err != nil {
+			return nil, err
+		}
+		value, err := e.exportType(t.ValueType)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewDictionaryType(key, value), nil
+	case *interpreter.InclusiveRangeStaticType:
+		element, err := e.exportType(t.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewInclusiveRangeType(element), nil
+	case *interpreter.CapabilityStaticType:
+		borrowType, err := e.exportType(t.BorrowType)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewCapabilityType(borrowType), nil
+	case *interpreter.ReferenceStaticType:
+		referenced, err := e.exportType(t.ReferencedType)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewReferenceType(exportAuthorization(t.Authorization), referenced), nil
+	case *interpreter.IntersectionStaticType:
+		types := make([]cadence.Type, 0, len(t.Types))
+		for _, interfaceType := range t.Types {
+			exported, err := e.exportType(interfaceType)
+			if err != nil {
+				return nil, err
+			}
+			types = append(types, exported)
+		}
+		return cadence.NewIntersectionType(types), nil
+	case *interpreter.CompositeStaticType:
+		location, identifier := t.Location, t.QualifiedIdentifier
+		switch e.kinds[t.TypeID] {
+		case common.CompositeKindResource:
+			return cadence.NewResourceType(location, identifier, nil, nil), nil
+		case common.CompositeKindAttachment:
+			return cadence.NewAttachmentType(location, identifier, nil, nil, nil), nil
+		case common.CompositeKindEvent:
+			return cadence.NewEventType(location, identifier, nil, nil), nil
+		case common.CompositeKindContract:
+			return cadence.NewContractType(location, identifier, nil, nil), nil
+		case common.CompositeKindEnum:
+			return cadence.NewEnumType(location, identifier, nil, nil, nil), nil
+		default:
+			return cadence.NewStructType(location, identifier, nil, nil), nil
+		}
+	case *interpreter.InterfaceStaticType:
+		return cadence.NewStructInterfaceType(t.Location, t.QualifiedIdentifier, nil, nil), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", staticType)
+}
+
+func exportAuthorization(authorization interpreter.Authorization) cadence.Authorization {
+	switch a := authorization.(type) {
+	case interpreter.EntitlementSetAuthorization:
+		var entitlements []common.TypeID
+		a.Entitlements.Foreach(func(entitlement common.TypeID, _ struct{}) {
+			entitlements = append(entitlements, entitlement)
+		})
+		return cadence.NewEntitlementSetAuthorization(nil, entitlements, a.SetKind)
+	case interpreter.EntitlementMapAuthorization:
+		return cadence.NewEntitlementMapAuthorization(nil, a.TypeID)
+	default:
+		return cadence.UnauthorizedAccess
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package storage decodes the Cadence values stored by accounts from the registers of the
+// execution state, such as the payloads of the trie updates of execution data.
+//
+// Cadence values are stored in atree slabs, and large values span several registers. The values
+// are decoded without the contracts declaring their types: the types of composite values are
+// reconstructed from their stored type IDs and fields.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/onflow/atree"
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// pathDomains are the domains of the paths under which values are stored.
+var pathDomains = []common.PathDomain{
+	common.PathDomainStorage,
+	common.PathDomainPrivate,
+	common.PathDomainPublic,
+}
+
+// Storage is a read-only view of the Cadence values stored in a set of registers.
+type Storage struct {
+	storage     *runtime.Storage
+	interpreter *interpreter.Interpreter
+}
+
+// New returns the storage of the registers of the given payloads.
+//
+// The payloads must include all the registers of the values decoded: the root register of
+// the account storage, and the slabs of the values. The registers changed by a trie update are
+// sufficient when a value is stored or replaced, but not when an existing value is only updated.
+func New(payloads []*flow.Payload) (*Storage, error) {
+	registers := make(ledger)
+	for i, payload := range payloads {
+		change, err := flow.DecodePayload(payload)
+		if err != nil {
+			return nil, fmt.Errorf("storage: invalid payload %d: %w", i, err)
+		}
+		registers[change.RegisterID] = change.Value
+	}
+
+	storage := runtime.NewStorage(registers, nil, runtime.StorageConfig{})
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		common.StringLocation("storage"),
+		&interpreter.Config{Storage: storage},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: failed to create interpreter: %w", err)
+	}
+
+	return &Storage{
+		storage:     storage,
+		interpreter: inter,
+	}, nil
+}
+
+// Value returns the value stored by the account at the given path, or nil if there is none.
+func (s *Storage) Value(address flow.Address, path cadence.Path) (value cadence.Value, err error) {
+	defer recoverError(&err)
+
+	domain, ok := s.domain(address, path.Domain)
+	if !ok {
+		return nil, nil
+	}
+	stored := domain.ReadValue(nil, interpreter.StringStorageMapKey(path.Identifier))
+	if stored == nil {
+		return nil, nil
+	}
+	return newExporter(s.interpreter).export(stored)
+}
+
+// Values returns the values stored by the account, by path.
+func (s *Storage) Values(address flow.Address) (values map[cadence.Path]cadence.Value, err error) {
+	defer recoverError(&err)
+
+	values = make(map[cadence.Path]cadence.Value)
+	exporter := newExporter(s.interpreter)
+	for _, pathDomain := range pathDomains {
+		domain, ok := s.domain(address, pathDomain)
+		if !ok {
+			continue
+		}
+
+		iterator := domain.Iterator(nil)
+		for {
+			key, stored := iterator.Next()
+			if key == nil {
+				break
+			}
+			identifier, ok := key.(interpreter.StringAtreeValue)
+			if !ok {
+				return nil, fmt.Errorf("storage: invalid key %v in domain %s", key, pathDomain.Identifier())
+			}
+
+			path := cadence.Path{Domain: pathDomain, Identifier: string(identifier)}
+			value, err := exporter.export(stored)
+			if err != nil {
+				return nil, fmt.Errorf("storage: failed to decode value at %s: %w", path, err)
+			}
+			values[path] = value
+		}
+	}
+	return values, nil
+}
+
+// domain returns the storage map of the domain of the account, if it exists.
+func (s *Storage) domain(address flow.Address, domain common.PathDomain) (*interpreter.DomainStorageMap, bool) {
+	domainMap := s.storage.GetDomainStorageMap(
+		s.interpreter,
+		common.Address(address),
+		domain.StorageDomain(),
+		false,
+	)
+	return domainMap, domainMap != nil
+}
+
+// recoverError recovers from the panics of the Cadence storage, such as for missing slabs.
+func recoverError(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("storage: failed to read value: %v", r)
+	}
+}
+
+// ledger is a read-only atree ledger of registers.
+type ledger map[flow.RegisterID][]byte
+
+var _ atree.Ledger = ledger{}
+
+func (l ledger) GetValue(owner, key []byte) ([]byte, error) {
+	return l[flow.RegisterID{Owner: flow.BytesToAddress(owner), Key: string(key)}], nil
+}
+
+func (l ledger) SetValue(_, _, _ []byte) error {
+	return fmt.Errorf("storage is read-only")
+}
+
+func (l ledger) ValueExists(owner, key []byte) (bool, error) {
+	value, err := l.GetValue(owner, key)
+	return len(value) > 0, err
+}
+
+func (l ledger) AllocateSlabIndex(_ []byte) (atree.SlabIndex, error) {
+	return atree.SlabIndex{}, fmt.Errorf("storage is read-only")
+}
@@ -0,0 +1,171 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package storage_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/storage"
+)
+
+// account is the service account of the emulator, with the values stored by the test transaction
+// of testdata/payloads.json.
+var account = flow.HexToAddress("f8d6e0586b0a20c7")
+
+func loadPayloads(t *testing.T) []*flow.Payload {
+	data, err := os.ReadFile("testdata/payloads.json")
+	require.NoError(t, err)
+
+	var payloads []*flow.Payload
+	require.NoError(t, json.Unmarshal(data, &payloads))
+	return payloads
+}
+
+func storagePath(identifier string) cadence.Path {
+	return cadence.Path{Domain: common.PathDomainStorage, Identifier: identifier}
+}
+
+func TestStorage_Value(t *testing.T) {
+	s, err := storage.New(loadPayloads(t))
+	require.NoError(t, err)
+
+	t.Run("Array", func(t *testing.T) {
+		value, err := s.Value(account, storagePath("numbers"))
+		require.NoError(t, err)
+		array, ok := value.(cadence.Array)
+		require.True(t, ok)
+		require.Len(t, array.Values, 1000)
+		assert.Equal(t, cadence.UInt64(0), array.Values[0])
+		assert.Equal(t, cadence.UInt64(999), array.Values[999])
+		assert.Equal(t, "[UInt64]", array.Type().ID())
+	})
+
+	t.Run("Dictionary", func(t *testing.T) {
+		value, err := s.Value(account, storagePath("names"))
+		require.NoError(t, err)
+		dictionary, ok := value.(cadence.Dictionary)
+		require.True(t, ok)
+		assert.ElementsMatch(t, []cadence.KeyValuePair{
+			{Key: cadence.String("one"), Value: cadence.NewInt(1)},
+			{Key: cadence.String("two"), Value: cadence.NewInt(2)},
+		}, dictionary.Pairs)
+	})
+
+	t.Run("Struct", func(t *testing.T) {
+		value, err := s.Value(account, storagePath("point"))
+		require.NoError(t, err)
+		point, ok := value.(cadence.Struct)
+		require.True(t, ok)
+		assert.Equal(t, "A.f8d6e0586b0a20c7.StorageTest.Point", point.Type().ID())
+
+		fields := cadence.FieldsMappedByName(point)
+		assert.Equal(t, cadence.NewInt(1), fields["x"])
+		assert.Equal(t, cadence.NewInt(-2), fields["y"])
+	})
+
+	t.Run("Nested resources", func(t *testing.T) {
+		value, err := s.Value(account, storagePath("box"))
+		require.NoError(t, err)
+		box, ok := value.(cadence.Resource)
+		require.True(t, ok)
+
+		fields := cadence.FieldsMappedByName(box)
+		assert.Equal(t, cadence.String("outer"), fields["label"])
+		boxes, ok := fields["boxes"].(cadence.Dictionary)
+		require.True(t, ok)
+		require.Len(t, boxes.Pairs, 1)
+		assert.Equal(t, cadence.UInt64(7), boxes.Pairs[0].Key)
+		assert.Equal(t, "{UInt64:A.f8d6e0586b0a20c7.StorageTest.Box}", boxes.Type().ID())
+		// the kind of the element type is learned from the elements
+		assert.IsType(t, &cadence.ResourceType{}, boxes.DictionaryType.ElementType)
+
+		inner, ok := boxes.Pairs[0].Value.(cadence.Resource)
+		require.True(t, ok)
+		assert.Equal(t, cadence.String("inner"), cadence.SearchFieldByName(inner, "label"))
+	})
+
+	t.Run("Vault balance", func(t *testing.T) {
+		value, err := s.Value(account, storagePath("flowTokenVault"))
+		require.NoError(t, err)
+		vault, ok := value.(cadence.Resource)
+		require.True(t, ok)
+		assert.Equal(t, "A.0ae53cb6e3f42a79.FlowToken.Vault", vault.Type().ID())
+
+		balance, err := cadence.NewUFix64("1000000000.0")
+		require.NoError(t, err)
+		assert.Equal(t, balance, cadence.SearchFieldByName(vault, "balance"))
+	})
+
+	t.Run("Capability", func(t *testing.T) {
+		value, err := s.Value(account, cadence.Path{Domain: common.PathDomainPublic, Identifier: "point"})
+		require.NoError(t, err)
+		capability, ok := value.(cadence.Capability)
+		require.True(t, ok)
+		assert.Equal(t, cadence.Address(account), capability.Address)
+		assert.Equal(t, "Capability<&A.f8d6e0586b0a20c7.StorageTest.Point>", capability.Type().ID())
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		value, err := s.Value(account, storagePath("missing"))
+		require.NoError(t, err)
+		assert.Nil(t, value)
+
+		value, err = s.Value(flow.HexToAddress("01"), storagePath("numbers"))
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+}
+
+func TestStorage_Values(t *testing.T) {
+	s, err := storage.New(loadPayloads(t))
+	require.NoError(t, err)
+
+	values, err := s.Values(account)
+	require.NoError(t, err)
+	for _, identifier := range []string{"numbers", "names", "point", "box", "flowTokenVault"} {
+		assert.Contains(t, values, storagePath(identifier))
+	}
+	assert.Contains(t, values, cadence.Path{Domain: common.PathDomainPublic, Identifier: "point"})
+}
+
+func TestStorage_MissingSlab(t *testing.T) {
+	// remove a slab of the array of numbers
+	var payloads []*flow.Payload
+	for _, payload := range loadPayloads(t) {
+		change, err := flow.DecodePayload(payload)
+		require.NoError(t, err)
+		if change.Kind == flow.RegisterKindStorageSlab && change.Index == 0x7d {
+			continue
+		}
+		payloads = append(payloads, payload)
+	}
+
+	s, err := storage.New(payloads)
+	require.NoError(t, err)
+
+	_, err = s.Value(account, storagePath("numbers"))
+	assert.Error(t, err)
+}
@@ -21,7 +21,6 @@ package templates
 import (
 	"encoding/hex"
 	"fmt"
-	"strings"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/common"
@@ -225,24 +224,15 @@ func CreateAccountAndFund(
 	if amount != "" && network == flow.Mainnet || network == flow.Testnet {
 		script = templates.CreateAccountFunding
 		// replace the imports on supported networks
-		replaces := map[flow.ChainID]map[string]string{
-			flow.Testnet: {
-				"FlowToken":     "0x7e60df042a9c0868", // https://developers.flow.com/flow/core-contracts/flow-token
-				"FungibleToken": "0x9a0766d93b6608b7", // https://developers.flow.com/flow/core-contracts/fungible-token
-			},
-			flow.Mainnet: {
-				"FlowToken":     "0x1654653399040a61",
-				"FungibleToken": "0xf233dcee88fe0abe",
-			},
-		}[network]
-
-		for contract, address := range replaces {
-			script = strings.ReplaceAll(
-				script,
-				fmt.Sprintf(`"%s"`, contract),
-				fmt.Sprintf(`%s from %s`, contract, address),
-			)
+		resolver, err := flow.NewImportResolver(network)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := resolver.Resolve([]byte(script))
+		if err != nil {
+			return nil, fmt.Errorf("cannot create CreateAccount transaction: %w", err)
 		}
+		script = string(resolved)
 
 		val, err := cadence.NewUFix64(amount)
 		if err != nil {
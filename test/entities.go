@@ -174,6 +174,7 @@ func (g *Blocks) New() *flow.Block {
 		BlockID:          g.ids.New(),
 		Chunks:           []*flow.Chunk{g.chunks.New()},
 		ServiceEvents:    serviceEvents,
+		ExecutionDataID:  g.ids.New(),
 	}}
 
 	payload := flow.BlockPayload{
@@ -306,6 +307,7 @@ func (g *CollectionGuarantees) New() *flow.CollectionGuarantee {
 		ReferenceBlockID: g.ids.New(),
 		Signature:        g.sigs.New()[0],
 		SignerIndices:    g.bytes.New(),
+		ClusterChainID:   flow.ChainID("cluster-0"),
 	}
 }
 
@@ -691,5 +693,6 @@ func (g *ChuckExecutionResult) New() *flow.Chunk {
 		NumberOfTransactions: 42,
 		Index:                42,
 		EndState:             flow.StateCommitment(g.ids.New()),
+		ServiceEventCount:    1,
 	}
 }
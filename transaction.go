@@ -20,6 +20,7 @@ package flow
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -314,9 +315,20 @@ func (t *Transaction) refreshSignerIndex() {
 //
 // This function returns an error if the signature cannot be generated.
 func (t *Transaction) SignPayload(address Address, keyIndex uint32, signer crypto.Signer) error {
+	return t.SignPayloadContext(context.Background(), address, keyIndex, signer)
+}
+
+// SignPayloadContext signs the transaction payload (TransactionDomainTag + payload) with the specified account key,
+// using the given context for the signing request.
+//
+// Signers that do not implement crypto.ContextSigner are adapted with crypto.NewContextSigner.
+//
+// This function returns an error if the signature cannot be generated or if the context
+// is done before the signature is generated.
+func (t *Transaction) SignPayloadContext(ctx context.Context, address Address, keyIndex uint32, signer crypto.Signer) error {
 	message := t.PayloadMessage()
 	message = append(TransactionDomainTag[:], message...)
-	sig, err := signer.Sign(message)
+	sig, err := crypto.NewContextSigner(signer).SignContext(ctx, message)
 	if err != nil {
 		// TODO: wrap error
 		return err
@@ -334,9 +346,20 @@ func (t *Transaction) SignPayload(address Address, keyIndex uint32, signer crypt
 //
 // This function returns an error if the signature cannot be generated.
 func (t *Transaction) SignEnvelope(address Address, keyIndex uint32, signer crypto.Signer) error {
+	return t.SignEnvelopeContext(context.Background(), address, keyIndex, signer)
+}
+
+// SignEnvelopeContext signs the full transaction (TransactionDomainTag + payload + payload signatures) with the
+// specified account key, using the given context for the signing request.
+//
+// Signers that do not implement crypto.ContextSigner are adapted with crypto.NewContextSigner.
+//
+// This function returns an error if the signature cannot be generated or if the context
+// is done before the signature is generated.
+func (t *Transaction) SignEnvelopeContext(ctx context.Context, address Address, keyIndex uint32, signer crypto.Signer) error {
 	message := t.EnvelopeMessage()
 	message = append(TransactionDomainTag[:], message...)
-	sig, err := signer.Sign(message)
+	sig, err := crypto.NewContextSigner(signer).SignContext(ctx, message)
 	if err != nil {
 		// TODO: wrap error
 		return err
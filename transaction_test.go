@@ -19,6 +19,7 @@
 package flow_test
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"testing"
@@ -732,6 +733,51 @@ func TestTransaction_SignatureOrdering(t *testing.T) {
 	assert.Equal(t, authorizerAddress, signatureB.Address)
 }
 
+func TestTransaction_SignContext(t *testing.T) {
+	addresses := test.AddressGenerator()
+	address := addresses.New()
+
+	newTx := func() *flow.Transaction {
+		return flow.NewTransaction().
+			SetScript([]byte(`transaction { execute { log("Hello, World!") } }`)).
+			SetProposalKey(address, 0, 42).
+			SetPayer(address).
+			AddAuthorizer(address)
+	}
+
+	t.Run("Payload", func(t *testing.T) {
+		tx := newTx()
+		err := tx.SignPayloadContext(context.Background(), address, 0, test.MockSigner([]byte{1}))
+		require.NoError(t, err)
+
+		require.Len(t, tx.PayloadSignatures, 1)
+		assert.Equal(t, []byte{1}, tx.PayloadSignatures[0].Signature)
+	})
+
+	t.Run("Envelope", func(t *testing.T) {
+		tx := newTx()
+		err := tx.SignEnvelopeContext(context.Background(), address, 0, test.MockSigner([]byte{2}))
+		require.NoError(t, err)
+
+		require.Len(t, tx.EnvelopeSignatures, 1)
+		assert.Equal(t, []byte{2}, tx.EnvelopeSignatures[0].Signature)
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		tx := newTx()
+		err := tx.SignPayloadContext(ctx, address, 0, test.MockSigner([]byte{1}))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, tx.PayloadSignatures)
+
+		err = tx.SignEnvelopeContext(ctx, address, 0, test.MockSigner([]byte{2}))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, tx.EnvelopeSignatures)
+	})
+}
+
 var sig, _ = hex.DecodeString("f7225388c1d69d57e6251c9fda50cbbf9e05131e5adb81e5aa0422402f048162")
 
 func baseTx() *flow.Transaction {
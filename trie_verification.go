@@ -0,0 +1,474 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/onflow/crypto/hash"
+)
+
+// ErrInvalidTrieUpdate is returned when a trie update or a trie proof doesn't match the state
+// commitments it is verified against.
+var ErrInvalidTrieUpdate = errors.New("invalid trie update")
+
+// trieHeight is the height of the root of the execution state trie, whose leaves are at height 0.
+const trieHeight = 256
+
+// trieProofEncodingVersion and the entity types are from the ledger encoding of the protocol.
+const (
+	trieProofEncodingVersion   = 0
+	trieProofEncodingType      = 7
+	trieBatchProofEncodingType = 8
+)
+
+// trieDefaultHashes are the hashes of empty subtries, by height.
+var trieDefaultHashes [trieHeight + 1][32]byte
+
+func init() {
+	hash.ComputeSHA3_256(&trieDefaultHashes[0], []byte("default:"))
+	for i := 1; i <= trieHeight; i++ {
+		trieDefaultHashes[i] = trieNodeHash(trieDefaultHashes[i-1], trieDefaultHashes[i-1])
+	}
+}
+
+// TrieProof is a proof of the value of a register in the execution state trie, at a state commitment.
+type TrieProof struct {
+	// Path is the path of the register in the trie.
+	Path []byte
+	// Payload is the register, with an empty value if the register doesn't exist.
+	Payload *Payload
+	// Interims are the hashes of the siblings which aren't empty subtries, from the root down to the register.
+	Interims [][]byte
+	// Inclusion is true if the register exists in the trie.
+	Inclusion bool
+	// Flags has a bit set for each step from the root whose sibling is in the interims.
+	Flags []byte
+	// Steps is the number of steps from the root down to the register.
+	Steps uint8
+}
+
+// TrieBatchProof is a list of proofs of registers at the same state commitment.
+type TrieBatchProof struct {
+	Proofs []*TrieProof
+}
+
+// Path returns the path of the register of the payload in the execution state trie, which is
+// the SHA3-256 hash of the canonical form of its key.
+func (p *Payload) Path() []byte {
+	var key []byte
+	for _, part := range p.KeyPart {
+		key = append(key, '/')
+		key = strconv.AppendUint(key, uint64(part.Type), 10)
+		key = append(key, '/')
+		key = append(key, part.Value...)
+	}
+
+	var path [32]byte
+	hash.ComputeSHA3_256(&path, key)
+	return path[:]
+}
+
+// Verify checks that the register of the proof has the value of its payload at the given state commitment.
+func (p *TrieProof) Verify(state StateCommitment) error {
+	if err := p.check(); err != nil {
+		return err
+	}
+
+	path := trieHash(p.Path)
+	leafHeight := trieHeight - int(p.Steps)
+	computed := trieCompactLeafHash(path, p.Payload.Value, leafHeight)
+
+	// the interims are ordered from the root, and are consumed from the leaf up
+	index := len(p.Interims) - 1
+	for h := leafHeight + 1; h <= trieHeight; h++ {
+		sibling := trieDefaultHashes[h-1]
+		if readBit(p.Flags, trieHeight-h) == 1 {
+			if index < 0 {
+				return fmt.Errorf("%w: proof of path %x has too few interims", ErrInvalidTrieUpdate, p.Path)
+			}
+			sibling = trieHash(p.Interims[index])
+			index--
+		}
+		if readBit(path[:], trieHeight-h) == 1 {
+			computed = trieNodeHash(sibling, computed)
+		} else {
+			computed = trieNodeHash(computed, sibling)
+		}
+	}
+
+	if index >= 0 {
+		return fmt.Errorf("%w: proof of path %x has too many interims", ErrInvalidTrieUpdate, p.Path)
+	}
+	if computed != state {
+		return fmt.Errorf("%w: proof of path %x doesn't match state commitment %x", ErrInvalidTrieUpdate, p.Path, state[:])
+	}
+	return nil
+}
+
+// check checks that the proof is well-formed.
+func (p *TrieProof) check() error {
+	if len(p.Path) != 32 {
+		return fmt.Errorf("%w: proof path has length %d, expected 32 bytes", ErrInvalidTrieUpdate, len(p.Path))
+	}
+	if p.Payload == nil {
+		return fmt.Errorf("%w: proof of path %x has no payload", ErrInvalidTrieUpdate, p.Path)
+	}
+	// the payload of a register which doesn't exist has no key
+	if len(p.Payload.KeyPart) > 0 && !bytes.Equal(p.Payload.Path(), p.Path) {
+		return fmt.Errorf("%w: proof of path %x has the payload of path %x", ErrInvalidTrieUpdate, p.Path, p.Payload.Path())
+	}
+	if len(p.Flags) != 32 {
+		return fmt.Errorf("%w: proof of path %x has flags of length %d, expected 32 bytes", ErrInvalidTrieUpdate, p.Path, len(p.Flags))
+	}
+	for _, interim := range p.Interims {
+		if len(interim) != 32 {
+			return fmt.Errorf("%w: proof of path %x has an interim of length %d, expected 32 bytes", ErrInvalidTrieUpdate, p.Path, len(interim))
+		}
+	}
+	return nil
+}
+
+// Verify checks that all the proofs of the batch match the given state commitment.
+func (bp *TrieBatchProof) Verify(state StateCommitment) error {
+	for _, p := range bp.Proofs {
+		if err := p.Verify(state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyTrieUpdate checks that the trie update of the chunk execution data updates the execution
+// state from the start state of the chunk to its end state.
+//
+// The paths of the update are checked against the keys of its payloads. Computing the end state
+// requires the part of the trie holding the updated registers at the start state, given by a batch
+// proof of the updated registers at the start state, as included in chunk data packs. The proof
+// may be nil if the chunk doesn't update any register.
+//
+// The chunk is only trusted if its execution result is sealed, or its receipt is verified.
+// ErrInvalidTrieUpdate is returned if the update doesn't match the chunk.
+func VerifyTrieUpdate(chunk *Chunk, data *ChunkExecutionData, proof *TrieBatchProof) error {
+	update := data.TrieUpdate
+	if update == nil {
+		update = &TrieUpdate{RootHash: chunk.StartState[:]}
+	}
+
+	if !bytes.Equal(update.RootHash, chunk.StartState[:]) {
+		return fmt.Errorf(
+			"%w: trie update of root %x doesn't start from the start state %x of chunk %d",
+			ErrInvalidTrieUpdate, update.RootHash, chunk.StartState[:], chunk.Index,
+		)
+	}
+	if len(update.Paths) != len(update.Payloads) {
+		return fmt.Errorf(
+			"%w: trie update has %d paths for %d payloads",
+			ErrInvalidTrieUpdate, len(update.Paths), len(update.Payloads),
+		)
+	}
+	for i, path := range update.Paths {
+		if !bytes.Equal(path, update.Payloads[i].Path()) {
+			return fmt.Errorf(
+				"%w: path %x of trie update doesn't match the key of its payload %d",
+				ErrInvalidTrieUpdate, path, i,
+			)
+		}
+	}
+
+	if len(update.Paths) == 0 {
+		if chunk.StartState != chunk.EndState {
+			return fmt.Errorf(
+				"%w: empty trie update doesn't reach the end state %x of chunk %d",
+				ErrInvalidTrieUpdate, chunk.EndState[:], chunk.Index,
+			)
+		}
+		return nil
+	}
+	if proof == nil {
+		return fmt.Errorf("missing proof of the registers updated by chunk %d", chunk.Index)
+	}
+
+	// each proof is checked on its own so that no proof is shadowed in the partial trie
+	if err := proof.Verify(chunk.StartState); err != nil {
+		return err
+	}
+	trie, err := newPartialTrie(proof)
+	if err != nil {
+		return err
+	}
+	if trie.root.computeHash() != chunk.StartState {
+		return fmt.Errorf(
+			"%w: proof doesn't match the start state %x of chunk %d",
+			ErrInvalidTrieUpdate, chunk.StartState[:], chunk.Index,
+		)
+	}
+
+	for i, path := range update.Paths {
+		leaf, ok := trie.leaves[trieHash(path)]
+		if !ok {
+			return fmt.Errorf("%w: proof is missing updated path %x", ErrInvalidTrieUpdate, path)
+		}
+		leaf.hash = trieCompactLeafHash(trieHash(path), update.Payloads[i].Value, leaf.height)
+	}
+
+	end := trie.root.computeHash()
+	if end != chunk.EndState {
+		return fmt.Errorf(
+			"%w: trie update reaches state %x instead of the end state %x of chunk %d",
+			ErrInvalidTrieUpdate, end[:], chunk.EndState[:], chunk.Index,
+		)
+	}
+	return nil
+}
+
+// partialTrie is the part of the execution state trie covered by a batch proof, where the
+// subtries outside of the proven paths are only known by their hashes.
+type partialTrie struct {
+	root   *partialTrieNode
+	leaves map[[32]byte]*partialTrieNode
+}
+
+type partialTrieNode struct {
+	left, right *partialTrieNode
+	height      int
+	hash        [32]byte
+	leaf        bool
+}
+
+// newPartialTrie builds the partial trie of the registers of the verified proofs.
+func newPartialTrie(proof *TrieBatchProof) (*partialTrie, error) {
+	trie := &partialTrie{
+		root:   &partialTrieNode{height: trieHeight, hash: trieDefaultHashes[trieHeight]},
+		leaves: make(map[[32]byte]*partialTrieNode, len(proof.Proofs)),
+	}
+
+	for _, p := range proof.Proofs {
+		path := trieHash(p.Path)
+		index := 0
+		node := trie.root
+		for step := 0; step < int(p.Steps); step++ {
+			if node.leaf {
+				return nil, fmt.Errorf("%w: proof of path %x goes below the register of another proof", ErrInvalidTrieUpdate, p.Path)
+			}
+			sibling := trieDefaultHashes[node.height-1]
+			if readBit(p.Flags, step) == 1 {
+				sibling = trieHash(p.Interims[index])
+				index++
+			}
+			if node.left == nil {
+				node.left = &partialTrieNode{height: node.height - 1, hash: sibling}
+				node.right = &partialTrieNode{height: node.height - 1, hash: sibling}
+				if readBit(path[:], step) == 1 {
+					node.right.hash = trieDefaultHashes[node.height-1]
+				} else {
+					node.left.hash = trieDefaultHashes[node.height-1]
+				}
+			}
+			if readBit(path[:], step) == 1 {
+				node = node.right
+			} else {
+				node = node.left
+			}
+		}
+
+		if node.left != nil {
+			return nil, fmt.Errorf("%w: proof of path %x ends above other proofs", ErrInvalidTrieUpdate, p.Path)
+		}
+		node.hash = trieCompactLeafHash(path, p.Payload.Value, node.height)
+		node.leaf = true
+		trie.leaves[path] = node
+	}
+
+	return trie, nil
+}
+
+// computeHash recomputes the hashes of the node and of its descendants.
+func (n *partialTrieNode) computeHash() [32]byte {
+	if n.left != nil {
+		n.hash = trieNodeHash(n.left.computeHash(), n.right.computeHash())
+	}
+	return n.hash
+}
+
+// trieCompactLeafHash returns the hash of the subtrie at the given height holding only the register
+// at the path, or of the empty subtrie if the value is empty.
+func trieCompactLeafHash(path [32]byte, value []byte, height int) [32]byte {
+	if len(value) == 0 {
+		return trieDefaultHashes[height]
+	}
+
+	var computed [32]byte
+	hash.ComputeSHA3_256(&computed, append(path[:], value...))
+	for h := 1; h <= height; h++ {
+		if readBit(path[:], trieHeight-h) == 1 {
+			computed = trieNodeHash(trieDefaultHashes[h-1], computed)
+		} else {
+			computed = trieNodeHash(computed, trieDefaultHashes[h-1])
+		}
+	}
+	return computed
+}
+
+func trieNodeHash(left, right [32]byte) [32]byte {
+	var h [32]byte
+	hash.ComputeSHA3_256(&h, append(left[:], right[:]...))
+	return h
+}
+
+func trieHash(b []byte) [32]byte {
+	var h [32]byte
+	copy(h[:], b)
+	return h
+}
+
+// DecodeTrieProof decodes a trie proof in the ledger encoding of the protocol.
+func DecodeTrieProof(b []byte) (*TrieProof, error) {
+	r := &trieProofReader{b: b}
+	r.header(trieProofEncodingType)
+	proof := r.proof()
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode trie proof: %w", r.err)
+	}
+	return proof, nil
+}
+
+// DecodeTrieBatchProof decodes a trie batch proof in the ledger encoding of the protocol, as
+// included in chunk data packs.
+func DecodeTrieBatchProof(b []byte) (*TrieBatchProof, error) {
+	r := &trieProofReader{b: b}
+	r.header(trieBatchProofEncodingType)
+	count := r.uint32()
+	batch := &TrieBatchProof{}
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		proof := &trieProofReader{b: r.bytes(int(r.uint64()))}
+		batch.Proofs = append(batch.Proofs, proof.proof())
+		if proof.err != nil {
+			r.err = fmt.Errorf("proof %d: %w", i, proof.err)
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode trie batch proof: %w", r.err)
+	}
+	return batch, nil
+}
+
+// trieProofReader reads the fields of encoded trie proofs, recording the first error.
+type trieProofReader struct {
+	b   []byte
+	err error
+}
+
+func (r *trieProofReader) header(typ uint8) {
+	if version := r.uint16(); r.err == nil && version != trieProofEncodingVersion {
+		r.err = fmt.Errorf("unsupported encoding version %d", version)
+	}
+	if t := r.uint8(); r.err == nil && t != typ {
+		r.err = fmt.Errorf("unexpected entity type %d, expected %d", t, typ)
+	}
+}
+
+func (r *trieProofReader) proof() *TrieProof {
+	proof := &TrieProof{}
+	proof.Inclusion = r.uint8()&0x80 != 0
+	proof.Steps = r.uint8()
+	proof.Flags = r.bytes(int(r.uint8()))
+	proof.Path = r.bytes(int(r.uint16()))
+
+	payload := &trieProofReader{b: r.bytes(int(r.uint64()))}
+	proof.Payload = payload.payload()
+	if payload.err != nil && r.err == nil {
+		r.err = fmt.Errorf("payload: %w", payload.err)
+	}
+
+	count := int(r.uint8())
+	for i := 0; i < count && r.err == nil; i++ {
+		proof.Interims = append(proof.Interims, r.bytes(int(r.uint16())))
+	}
+	return proof
+}
+
+// payload reads a payload in version 0 of the payload encoding, used by trie proofs.
+func (r *trieProofReader) payload() *Payload {
+	payload := &Payload{}
+
+	key := &trieProofReader{b: r.bytes(int(r.uint32()))}
+	if len(key.b) > 0 {
+		count := int(key.uint16())
+		for i := 0; i < count && key.err == nil; i++ {
+			part := &trieProofReader{b: key.bytes(int(key.uint32()))}
+			typ := part.uint16()
+			if part.err != nil {
+				key.err = part.err
+				break
+			}
+			payload.KeyPart = append(payload.KeyPart, &KeyPart{Type: typ, Value: part.b})
+		}
+	}
+	if key.err != nil && r.err == nil {
+		r.err = fmt.Errorf("key: %w", key.err)
+	}
+
+	payload.Value = r.bytes(int(r.uint64()))
+	return payload
+}
+
+func (r *trieProofReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.b) {
+		r.err = fmt.Errorf("unexpected end of data, reading %d bytes out of %d", n, len(r.b))
+		return nil
+	}
+	b := r.b[:n:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *trieProofReader) uint8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *trieProofReader) uint16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *trieProofReader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *trieProofReader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
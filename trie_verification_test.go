@@ -0,0 +1,210 @@
+/*
+ * Flow Go SDK
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package flow_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// trieUpdateVector is a trie update computed by the ledger of the protocol, with the batch proof
+// of the updated registers at the start state.
+type trieUpdateVector struct {
+	StartState string
+	EndState   string
+	Paths      []string
+	Payloads   []struct {
+		KeyParts []struct {
+			Type  uint16
+			Value string
+		}
+		Value string
+	}
+	BatchProof string
+	Proof      string
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func loadTrieUpdateVector(t *testing.T) (*flow.Chunk, *flow.ChunkExecutionData, trieUpdateVector) {
+	data, err := os.ReadFile("testdata/trie_update.json")
+	require.NoError(t, err)
+
+	var vector trieUpdateVector
+	require.NoError(t, json.Unmarshal(data, &vector))
+
+	chunk := &flow.Chunk{
+		StartState: flow.HexToStateCommitment(vector.StartState),
+		EndState:   flow.HexToStateCommitment(vector.EndState),
+	}
+	update := &flow.TrieUpdate{RootHash: mustDecodeHex(t, vector.StartState)}
+	for i, p := range vector.Payloads {
+		payload := &flow.Payload{Value: mustDecodeHex(t, p.Value)}
+		for _, part := range p.KeyParts {
+			payload.KeyPart = append(payload.KeyPart, &flow.KeyPart{Type: part.Type, Value: mustDecodeHex(t, part.Value)})
+		}
+		update.Paths = append(update.Paths, mustDecodeHex(t, vector.Paths[i]))
+		update.Payloads = append(update.Payloads, payload)
+	}
+
+	return chunk, &flow.ChunkExecutionData{TrieUpdate: update}, vector
+}
+
+func TestPayload_Path(t *testing.T) {
+	_, data, _ := loadTrieUpdateVector(t)
+	for i, payload := range data.TrieUpdate.Payloads {
+		assert.Equal(t, data.TrieUpdate.Paths[i], payload.Path())
+	}
+}
+
+func TestDecodeTrieProof(t *testing.T) {
+	chunk, data, vector := loadTrieUpdateVector(t)
+
+	proof, err := flow.DecodeTrieProof(mustDecodeHex(t, vector.Proof))
+	require.NoError(t, err)
+	assert.True(t, proof.Inclusion)
+	assert.Len(t, proof.Flags, 32)
+	require.NoError(t, proof.Verify(chunk.StartState))
+	assert.ErrorIs(t, proof.Verify(chunk.EndState), flow.ErrInvalidTrieUpdate)
+
+	// the proof has the value of the register before the update
+	var updated *flow.Payload
+	for i, path := range data.TrieUpdate.Paths {
+		if bytes.Equal(path, proof.Path) {
+			updated = data.TrieUpdate.Payloads[i]
+		}
+	}
+	require.NotNil(t, updated)
+	assert.Equal(t, updated.KeyPart, proof.Payload.KeyPart)
+	assert.NotEqual(t, updated.Value, proof.Payload.Value)
+
+	_, err = flow.DecodeTrieProof(mustDecodeHex(t, vector.Proof)[:40])
+	assert.Error(t, err)
+	_, err = flow.DecodeTrieProof(mustDecodeHex(t, vector.BatchProof))
+	assert.Error(t, err)
+}
+
+func TestDecodeTrieBatchProof(t *testing.T) {
+	chunk, data, vector := loadTrieUpdateVector(t)
+
+	batch, err := flow.DecodeTrieBatchProof(mustDecodeHex(t, vector.BatchProof))
+	require.NoError(t, err)
+	require.Len(t, batch.Proofs, len(data.TrieUpdate.Paths))
+	require.NoError(t, batch.Verify(chunk.StartState))
+
+	// registers created by the update are proven with an empty payload
+	var missing int
+	for _, proof := range batch.Proofs {
+		if len(proof.Payload.Value) == 0 {
+			assert.Empty(t, proof.Payload.KeyPart)
+			missing++
+		}
+	}
+	assert.Equal(t, 2, missing)
+}
+
+func TestVerifyTrieUpdate(t *testing.T) {
+	load := func(t *testing.T) (*flow.Chunk, *flow.ChunkExecutionData, *flow.TrieBatchProof) {
+		chunk, data, vector := loadTrieUpdateVector(t)
+		proof, err := flow.DecodeTrieBatchProof(mustDecodeHex(t, vector.BatchProof))
+		require.NoError(t, err)
+		return chunk, data, proof
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		chunk, data, proof := load(t)
+		assert.NoError(t, flow.VerifyTrieUpdate(chunk, data, proof))
+	})
+
+	t.Run("Empty update", func(t *testing.T) {
+		chunk, _, _ := load(t)
+		chunk.EndState = chunk.StartState
+		assert.NoError(t, flow.VerifyTrieUpdate(chunk, &flow.ChunkExecutionData{}, nil))
+
+		chunk, _, _ = load(t)
+		err := flow.VerifyTrieUpdate(chunk, &flow.ChunkExecutionData{}, nil)
+		assert.ErrorIs(t, err, flow.ErrInvalidTrieUpdate)
+	})
+
+	t.Run("Forged value", func(t *testing.T) {
+		chunk, data, proof := load(t)
+		data.TrieUpdate.Payloads[0].Value = []byte{0, 0, 0, 0, 0, 0, 0, 7}
+		err := flow.VerifyTrieUpdate(chunk, data, proof)
+		assert.ErrorIs(t, err, flow.ErrInvalidTrieUpdate)
+	})
+
+	t.Run("Dropped register", func(t *testing.T) {
+		chunk, data, proof := load(t)
+		data.TrieUpdate.Paths = data.TrieUpdate.Paths[1:]
+		data.TrieUpdate.Payloads = data.TrieUpdate.Payloads[1:]
+		err := flow.VerifyTrieUpdate(chunk, data, proof)
+		assert.ErrorIs(t, err, flow.ErrInvalidTrieUpdate)
+	})
+
+	t.Run("Forged key", func(t *testing.T) {
+		chunk, data, proof := load(t)
+		data.TrieUpdate.Payloads[0].KeyPart[1].Value = []byte("sn_1")
+		err := flow.VerifyTrieUpdate(chunk, data, proof)
+		assert.ErrorIs(t, err, flow.ErrInvalidTrieUpdate)
+	})
+
+	t.Run("Wrong start state", func(t *testing.T) {
+		chunk, data, proof := load(t)
+		chunk.StartState = chunk.EndState
+		err := flow.VerifyTrieUpdate(chunk, data, proof)
+		assert.ErrorIs(t, err, flow.ErrInvalidTrieUpdate)
+	})
+
+	t.Run("Wrong end state", func(t *testing.T) {
+		chunk, data, proof := load(t)
+		chunk.EndState = chunk.StartState
+		err := flow.VerifyTrieUpdate(chunk, data, proof)
+		assert.ErrorIs(t, err, flow.ErrInvalidTrieUpdate)
+	})
+
+	t.Run("Forged proof", func(t *testing.T) {
+		chunk, data, proof := load(t)
+		proof.Proofs[0].Interims[0][0] ^= 1
+		err := flow.VerifyTrieUpdate(chunk, data, proof)
+		assert.ErrorIs(t, err, flow.ErrInvalidTrieUpdate)
+	})
+
+	t.Run("Incomplete proof", func(t *testing.T) {
+		chunk, data, proof := load(t)
+		proof.Proofs = proof.Proofs[1:]
+		err := flow.VerifyTrieUpdate(chunk, data, proof)
+		assert.ErrorIs(t, err, flow.ErrInvalidTrieUpdate)
+	})
+
+	t.Run("Missing proof", func(t *testing.T) {
+		chunk, data, _ := load(t)
+		assert.Error(t, flow.VerifyTrieUpdate(chunk, data, nil))
+	})
+}